@@ -0,0 +1,83 @@
+package rpn
+
+import "math"
+
+// The built-in tables, which evaluators with nil tables share. They must never be modified.
+var (
+	defaultBinary    = builtinBinary()
+	defaultUnary     = builtinUnary()
+	defaultFunctions = builtinFunctions()
+	defaultConstants = builtinConstants()
+)
+
+// builtinBinary returns the built-in binary operators: addition (+), subtraction (-),
+// multiplication (*), division (/), floating-point remainder (%), and exponentiation (^), which
+// binds tightest and groups from the right.
+func builtinBinary() map[string]BinaryOp {
+	return map[string]BinaryOp{
+		"+": {Precedence: 1, Fn: func(a, b float64) (float64, error) { return a + b, nil }},
+		"-": {Precedence: 1, Fn: func(a, b float64) (float64, error) { return a - b, nil }},
+		"*": {Precedence: 2, Fn: func(a, b float64) (float64, error) { return a * b, nil }},
+		"/": {Precedence: 2, Fn: func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, ErrDivisionByZero
+			}
+			return a / b, nil
+		}},
+		"%": {Precedence: 2, Fn: func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, ErrDivisionByZero
+			}
+			return math.Mod(a, b), nil
+		}},
+		"^": {Precedence: 4, RightAssoc: true, Fn: func(a, b float64) (float64, error) {
+			return math.Pow(a, b), nil
+		}},
+	}
+}
+
+// builtinUnary returns the built-in unary operators: negation (-) and its counterpart (+). They
+// bind more tightly than every binary operator except ^, so -2^2 is -4.
+func builtinUnary() map[string]UnaryOp {
+	return map[string]UnaryOp{
+		"-": {Precedence: 3, Fn: func(a float64) (float64, error) { return -a, nil }},
+		"+": {Precedence: 3, Fn: func(a float64) (float64, error) { return a, nil }},
+	}
+}
+
+// builtinFunctions returns the built-in functions, mostly from package math.
+func builtinFunctions() map[string]Function {
+	unary := func(fn func(float64) float64) Function {
+		return Function{Arity: 1, Fn: func(args []float64) (float64, error) { return fn(args[0]), nil }}
+	}
+	binary := func(fn func(float64, float64) float64) Function {
+		return Function{Arity: 2, Fn: func(args []float64) (float64, error) {
+			return fn(args[0], args[1]), nil
+		}}
+	}
+
+	return map[string]Function{
+		"abs":   unary(math.Abs),
+		"ceil":  unary(math.Ceil),
+		"cos":   unary(math.Cos),
+		"exp":   unary(math.Exp),
+		"floor": unary(math.Floor),
+		"ln":    unary(math.Log),
+		"log10": unary(math.Log10),
+		"round": unary(math.Round),
+		"sin":   unary(math.Sin),
+		"sqrt":  unary(math.Sqrt),
+		"tan":   unary(math.Tan),
+		"max":   binary(math.Max),
+		"min":   binary(math.Min),
+		"pow":   binary(math.Pow),
+	}
+}
+
+// builtinConstants returns the built-in constants: pi and e.
+func builtinConstants() map[string]float64 {
+	return map[string]float64{
+		"pi": math.Pi,
+		"e":  math.E,
+	}
+}
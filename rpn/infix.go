@@ -0,0 +1,164 @@
+package rpn
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+// Infix tokenizes an infix expression and reorders its tokens into RPN for Evaluate, using the
+// shunting-yard algorithm. Binary operators follow the precedence and associativity in the
+// evaluator's tables. An operator where an operand is expected, such as the minus in "-x" or
+// "2 * -x", is unary. A name followed by an opening parenthesis is a function call, with its
+// arguments separated by commas; any other name is a variable.
+func (e *Evaluator) Infix(expr string) ([]Token, error) {
+	tokens, err := e.Tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrEmpty
+	}
+
+	var out []Token
+
+	// ops holds operators, functions, and opening parentheses that haven't been output yet. args
+	// holds one entry for each opening parenthesis on ops: the number of commas seen so far if it
+	// opens a function call, or -1 otherwise.
+	var ops stack.Stack[Token]
+	var args stack.Stack[int]
+
+	expectOperand := true
+	prev := TokenEnd
+	for i, t := range tokens {
+		switch t.Kind {
+		case TokenNumber, TokenIdent:
+			if !expectOperand {
+				return nil, &Error{Token: t, Err: ErrSyntax}
+			}
+
+			if t.Kind == TokenIdent && i+1 < len(tokens) && tokens[i+1].Kind == TokenLeftParen {
+				if _, ok := e.functions()[t.Text]; !ok {
+					return nil, &Error{Token: t, Err: ErrUnknownFunction}
+				}
+				t.Kind = TokenFunction
+				ops.Push(t)
+				break
+			}
+
+			out = append(out, t)
+			expectOperand = false
+
+		case TokenLeftParen:
+			if !expectOperand {
+				return nil, &Error{Token: t, Err: ErrSyntax}
+			}
+
+			if prev == TokenFunction {
+				args.Push(0)
+			} else {
+				args.Push(-1)
+			}
+			ops.Push(t)
+
+		case TokenComma:
+			if expectOperand || args.Empty() || args.Peek() < 0 {
+				return nil, &Error{Token: t, Err: ErrSyntax}
+			}
+
+			for ops.Peek().Kind != TokenLeftParen {
+				out = append(out, ops.Pop())
+			}
+			args.Push(args.Pop() + 1)
+			expectOperand = true
+
+		case TokenRightParen:
+			if args.Empty() {
+				return nil, &Error{Token: t, Err: ErrMismatchedParen}
+			}
+
+			// Only a function call can have nothing in its parentheses.
+			emptyCall := prev == TokenLeftParen && args.Peek() == 0
+			if expectOperand && !emptyCall {
+				return nil, &Error{Token: t, Err: ErrSyntax}
+			}
+
+			for ops.Peek().Kind != TokenLeftParen {
+				out = append(out, ops.Pop())
+			}
+			ops.Pop()
+
+			if commas := args.Pop(); commas >= 0 {
+				fn := ops.Pop()
+				n := commas + 1
+				if emptyCall {
+					n = 0
+				}
+				if arity := e.functions()[fn.Text].Arity; n != arity {
+					return nil, &Error{Token: fn, Err: fmt.Errorf("%w: need %d, have %d", ErrArity, arity, n)}
+				}
+				out = append(out, fn)
+			}
+			expectOperand = false
+
+		case TokenOperator:
+			if expectOperand {
+				if _, ok := e.unary()[t.Text]; ok {
+					t.Kind = TokenUnary
+					ops.Push(t)
+					break
+				}
+				if _, ok := e.binary()[t.Text]; ok {
+					return nil, &Error{Token: t, Err: ErrMissingOperand}
+				}
+				return nil, &Error{Token: t, Err: ErrUnknownOperator}
+			}
+
+			op, ok := e.binary()[t.Text]
+			if !ok {
+				// The operator is unary, but it follows an operand.
+				return nil, &Error{Token: t, Err: ErrSyntax}
+			}
+
+			for !ops.Empty() {
+				top := ops.Peek()
+				if top.Kind != TokenOperator && top.Kind != TokenUnary {
+					break
+				}
+
+				prec := e.precedence(top)
+				if prec < op.Precedence || (prec == op.Precedence && op.RightAssoc) {
+					break
+				}
+				out = append(out, ops.Pop())
+			}
+			ops.Push(t)
+			expectOperand = true
+		}
+
+		prev = t.Kind
+	}
+
+	if expectOperand {
+		return nil, &Error{Token: Token{Kind: TokenEnd, Pos: len(expr)}, Err: ErrMissingOperand}
+	}
+
+	for !ops.Empty() {
+		t := ops.Pop()
+		if t.Kind == TokenLeftParen {
+			return nil, &Error{Token: t, Err: ErrMismatchedParen}
+		}
+		out = append(out, t)
+	}
+
+	return out, nil
+}
+
+// precedence returns the precedence of an operator token.
+func (e *Evaluator) precedence(t Token) int {
+	if t.Kind == TokenUnary {
+		return e.unary()[t.Text].Precedence
+	}
+
+	return e.binary()[t.Text].Precedence
+}
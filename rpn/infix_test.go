@@ -0,0 +1,139 @@
+package rpn
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Eval tests that Eval evaluates infix expressions with the built-in tables.
+func Test_Eval(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		vars := map[string]float64{"x": 3, "y": 4, "pi": 3}
+		for _, test := range []struct {
+			expr string
+			want float64
+		}{
+			{"42", 42},
+			{"1 + 2 * 3", 7},
+			{"(1 + 2) * 3", 9},
+			{"10 - 4 - 3", 3},
+			{"2 ^ 3 ^ 2", 512},
+			{"(2 ^ 3) ^ 2", 64},
+			{"-2 ^ 2", -4},
+			{"(-2) ^ 2", 4},
+			{"2 ^ -1", 0.5},
+			{"-3 * 2", -6},
+			{"2 * -3", -6},
+			{"--3", 3},
+			{"+3 - -3", 6},
+			{"7 % 4", 3},
+			{"sqrt(x * x + y * y)", 5},
+			{"max(x, min(y, 10)) + 1", 5},
+			{"max((1), (2 + 3) * 2)", 10},
+			{"abs(-x)", 3},
+			{"round(2.5) + floor(-1.5) + ceil(1.2)", 3},
+			{"pi", 3},
+			{"e", math.E},
+			{"ln(e)", 1},
+			{"pow(2, 10)", 1024},
+		} {
+			v, err := Eval(test.expr, vars)
+			require.NoError(t, err, test.expr)
+			require.InDelta(t, test.want, v, 1e-9, test.expr)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, test := range []struct {
+			expr string
+			err  error
+			want Token
+		}{
+			{"1 +", ErrMissingOperand, Token{TokenEnd, "", 3}},
+			{"* 2", ErrMissingOperand, Token{TokenOperator, "*", 0}},
+			{"1 * * 2", ErrMissingOperand, Token{TokenOperator, "*", 4}},
+			{"2 3", ErrSyntax, Token{TokenNumber, "3", 2}},
+			{"2 (3)", ErrSyntax, Token{TokenLeftParen, "(", 2}},
+			{"()", ErrSyntax, Token{TokenRightParen, ")", 1}},
+			{"(1 +)", ErrSyntax, Token{TokenRightParen, ")", 4}},
+			{"(1, 2)", ErrSyntax, Token{TokenComma, ",", 2}},
+			{"1, 2", ErrSyntax, Token{TokenComma, ",", 1}},
+			{"max(, 1)", ErrSyntax, Token{TokenComma, ",", 4}},
+			{"max(1, )", ErrSyntax, Token{TokenRightParen, ")", 7}},
+			{"(1 + 2", ErrMismatchedParen, Token{TokenLeftParen, "(", 0}},
+			{"1 + 2)", ErrMismatchedParen, Token{TokenRightParen, ")", 5}},
+			{"nope(1)", ErrUnknownFunction, Token{TokenIdent, "nope", 0}},
+			{"z + 1", ErrUnknownVariable, Token{TokenIdent, "z", 0}},
+			{"max(1)", ErrArity, Token{TokenFunction, "max", 0}},
+			{"sqrt()", ErrArity, Token{TokenFunction, "sqrt", 0}},
+			{"abs(1, 2)", ErrArity, Token{TokenFunction, "abs", 0}},
+			{"1 / (2 - 2)", ErrDivisionByZero, Token{TokenOperator, "/", 2}},
+			{"1 % 0", ErrDivisionByZero, Token{TokenOperator, "%", 2}},
+		} {
+			_, err := Eval(test.expr, nil)
+			require.ErrorIs(t, err, test.err, test.expr)
+
+			var rerr *Error
+			require.True(t, errors.As(err, &rerr), test.expr)
+			require.Equal(t, test.want, rerr.Token, test.expr)
+		}
+
+		_, err := Eval(" ", nil)
+		require.ErrorIs(t, err, ErrEmpty)
+	})
+}
+
+// Test_Evaluator_Infix tests that Evaluator's Infix method reorders infix expressions into RPN.
+func Test_Evaluator_Infix(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		for _, test := range []struct {
+			expr string
+			want []string
+		}{
+			{"1 + 2 * 3", []string{"1", "2", "3", "*", "+"}},
+			{"(1 + 2) * 3", []string{"1", "2", "+", "3", "*"}},
+			{"a - b - c", []string{"a", "b", "-", "c", "-"}},
+			{"a ^ b ^ c", []string{"a", "b", "c", "^", "^"}},
+			{"-a ^ b", []string{"a", "b", "^", "-"}},
+			{"-a * b", []string{"a", "-", "b", "*"}},
+			{"max(a, b + 1) * c", []string{"a", "b", "1", "+", "max", "c", "*"}},
+		} {
+			var e Evaluator
+			tokens, err := e.Infix(test.expr)
+			require.NoError(t, err, test.expr)
+
+			var texts []string
+			for _, tok := range tokens {
+				texts = append(texts, tok.Text)
+			}
+			require.Equal(t, test.want, texts, test.expr)
+		}
+	})
+
+	t.Run("kinds", func(t *testing.T) {
+		var e Evaluator
+		tokens, err := e.Infix("-max(x, 2)")
+		require.NoError(t, err)
+		require.Equal(t, []Token{
+			{TokenIdent, "x", 5},
+			{TokenNumber, "2", 8},
+			{TokenFunction, "max", 1},
+			{TokenUnary, "-", 0},
+		}, tokens)
+	})
+
+	t.Run("reuse", func(t *testing.T) {
+		var e Evaluator
+		tokens, err := e.Infix("x * x")
+		require.NoError(t, err)
+
+		for x := 0.0; x < 10; x++ {
+			v, err := e.Evaluate(tokens, map[string]float64{"x": x})
+			require.NoError(t, err)
+			require.Equal(t, x*x, v)
+		}
+	})
+}
@@ -0,0 +1,323 @@
+// Package rpn evaluates arithmetic expressions written in Reverse Polish Notation (RPN), such as
+// "3 4 + 2 *", or in ordinary infix notation, such as "(3 + 4) * 2".
+package rpn
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+var (
+	// ErrSyntax is returned when an expression is malformed.
+	ErrSyntax = errors.New("rpn: syntax error")
+	// ErrEmpty is returned when an expression has no value.
+	ErrEmpty = errors.New("rpn: empty expression")
+	// ErrUnknownOperator is returned when an expression uses an operator that isn't in the
+	// evaluator's operator tables.
+	ErrUnknownOperator = errors.New("rpn: unknown operator")
+	// ErrUnknownFunction is returned when an expression calls a function that isn't in the
+	// evaluator's function table.
+	ErrUnknownFunction = errors.New("rpn: unknown function")
+	// ErrUnknownVariable is returned when an expression uses a name that isn't bound to a value.
+	ErrUnknownVariable = errors.New("rpn: unknown variable")
+	// ErrMissingOperand is returned when an operator or function doesn't have enough operands.
+	ErrMissingOperand = errors.New("rpn: missing operand")
+	// ErrExtraOperand is returned when an expression leaves more than one value.
+	ErrExtraOperand = errors.New("rpn: extra operand")
+	// ErrMismatchedParen is returned when an expression's parentheses don't pair up.
+	ErrMismatchedParen = errors.New("rpn: mismatched parenthesis")
+	// ErrArity is returned when a function is called with the wrong number of arguments.
+	ErrArity = errors.New("rpn: wrong number of arguments")
+	// ErrDivisionByZero is returned by the built-in division and modulo operators when the divisor
+	// is zero.
+	ErrDivisionByZero = errors.New("rpn: division by zero")
+)
+
+// An Error is an error caused by a particular token in an expression.
+type Error struct {
+	// Token is the offending token. If the expression ended unexpectedly, its kind is TokenEnd and
+	// its position is the length of the expression.
+	Token Token
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	if e.Token.Kind == TokenEnd {
+		return fmt.Sprintf("%v at end of expression", e.Err)
+	}
+
+	return fmt.Sprintf("%v: %q at offset %d", e.Err, e.Token.Text, e.Token.Pos)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// A BinaryOp is an operator that takes two operands.
+type BinaryOp struct {
+	// Precedence determines how tightly the operator binds in infix expressions: operators with
+	// higher precedence are applied first.
+	Precedence int
+	// RightAssoc makes the operator group from the right in infix expressions, so a^b^c means
+	// a^(b^c).
+	RightAssoc bool
+	// Fn applies the operator to its operands.
+	Fn func(a, b float64) (float64, error)
+}
+
+// A UnaryOp is an operator that takes one operand, written before it in infix expressions.
+type UnaryOp struct {
+	// Precedence determines how tightly the operator binds in infix expressions, compared to binary
+	// operators.
+	Precedence int
+	// Fn applies the operator to its operand.
+	Fn func(a float64) (float64, error)
+}
+
+// A Function is a named function that takes a fixed number of arguments.
+type Function struct {
+	// Arity is the number of arguments the function takes.
+	Arity int
+	// Fn applies the function to its arguments, which are in the order they were written.
+	Fn func(args []float64) (float64, error)
+}
+
+// An Evaluator evaluates expressions using tables of operators, functions, and constants. A nil
+// table means the evaluator uses the corresponding built-in table, so the zero value is ready to
+// use. An Evaluator is safe for concurrent use as long as its tables aren't modified.
+type Evaluator struct {
+	// Binary holds the binary operators, by symbol.
+	Binary map[string]BinaryOp
+	// Unary holds the unary operators, by symbol.
+	Unary map[string]UnaryOp
+	// Functions holds the functions, by name.
+	Functions map[string]Function
+	// Constants holds named values that are available in every expression. Variables passed to an
+	// evaluation take precedence over constants with the same name.
+	Constants map[string]float64
+}
+
+// New returns an evaluator with copies of the built-in tables, which can be modified to add,
+// replace, or remove operators, functions, and constants.
+func New() *Evaluator {
+	return &Evaluator{
+		Binary:    builtinBinary(),
+		Unary:     builtinUnary(),
+		Functions: builtinFunctions(),
+		Constants: builtinConstants(),
+	}
+}
+
+// Eval evaluates an infix expression with the built-in tables. See Evaluator.Eval.
+func Eval(expr string, vars map[string]float64) (float64, error) {
+	var e Evaluator
+	return e.Eval(expr, vars)
+}
+
+// EvalRPN evaluates an RPN expression with the built-in tables. See Evaluator.EvalRPN.
+func EvalRPN(expr string, vars map[string]float64) (float64, error) {
+	var e Evaluator
+	return e.EvalRPN(expr, vars)
+}
+
+// Eval evaluates an infix expression, such as "2 * (x + 1)", with names bound to the values in
+// vars. Errors caused by a particular token are *Errors.
+func (e *Evaluator) Eval(expr string, vars map[string]float64) (float64, error) {
+	tokens, err := e.Infix(expr)
+	if err != nil {
+		return 0, err
+	}
+
+	return e.Evaluate(tokens, vars)
+}
+
+// EvalRPN evaluates an RPN expression, such as "x 1 + 2 *", with names bound to the values in
+// vars. Errors caused by a particular token are *Errors.
+func (e *Evaluator) EvalRPN(expr string, vars map[string]float64) (float64, error) {
+	tokens, err := e.RPN(expr)
+	if err != nil {
+		return 0, err
+	}
+
+	return e.Evaluate(tokens, vars)
+}
+
+// RPN tokenizes an RPN expression and classifies its tokens for Evaluate. A + or - written directly
+// before a number, with no space between them, is the number's sign, so "3 -4 +" is -1. A name is
+// a function if it is in the function table, or a variable otherwise. Any other operator is binary
+// if it is in the binary operator table, or unary otherwise, so a symbol that is both is always
+// binary in RPN. Parentheses and commas aren't allowed.
+func (e *Evaluator) RPN(expr string) ([]Token, error) {
+	tokens, err := e.Tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	out := tokens[:0]
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+
+		switch t.Kind {
+		case TokenIdent:
+			if _, ok := e.functions()[t.Text]; ok {
+				t.Kind = TokenFunction
+			}
+		case TokenOperator:
+			if i+1 < len(tokens) && isSign(t, tokens[i+1]) {
+				next := tokens[i+1]
+				t = Token{Kind: TokenNumber, Text: expr[t.Pos : next.Pos+len(next.Text)], Pos: t.Pos}
+				i++
+				break
+			}
+			if _, ok := e.binary()[t.Text]; !ok {
+				t.Kind = TokenUnary
+			}
+		case TokenLeftParen, TokenRightParen, TokenComma:
+			return nil, &Error{Token: t, Err: ErrSyntax}
+		}
+
+		out = append(out, t)
+	}
+
+	return out, nil
+}
+
+// isSign returns true if t is a + or - written directly before the number next.
+func isSign(t, next Token) bool {
+	return (t.Text == "+" || t.Text == "-") && next.Kind == TokenNumber && next.Pos == t.Pos+1
+}
+
+// Evaluate evaluates tokens in RPN order, as returned by RPN or Infix, with names bound to the
+// values in vars. Evaluating the same tokens again with different variables skips tokenizing and
+// parsing the expression.
+func (e *Evaluator) Evaluate(tokens []Token, vars map[string]float64) (float64, error) {
+	var operands stack.Stack[float64]
+
+	// pop removes n operands for t, in the order they were pushed.
+	pop := func(t Token, n int) ([]float64, error) {
+		if have := operands.Count(); have < n {
+			return nil, &Error{Token: t, Err: fmt.Errorf("%w: need %d, have %d", ErrMissingOperand, n, have)}
+		}
+
+		args := operands.PopN(n)
+		for i, j := 0, len(args)-1; i < j; i, j = i+1, j-1 {
+			args[i], args[j] = args[j], args[i]
+		}
+
+		return args, nil
+	}
+
+	for _, t := range tokens {
+		var v float64
+		var err error
+
+		switch t.Kind {
+		case TokenNumber:
+			v, err = strconv.ParseFloat(t.Text, 64)
+			if err != nil {
+				err = ErrSyntax
+			}
+
+		case TokenIdent:
+			var ok bool
+			if v, ok = vars[t.Text]; !ok {
+				if v, ok = e.constants()[t.Text]; !ok {
+					err = ErrUnknownVariable
+				}
+			}
+
+		case TokenOperator:
+			op, ok := e.binary()[t.Text]
+			if !ok {
+				return 0, &Error{Token: t, Err: ErrUnknownOperator}
+			}
+			args, perr := pop(t, 2)
+			if perr != nil {
+				return 0, perr
+			}
+			v, err = op.Fn(args[0], args[1])
+
+		case TokenUnary:
+			op, ok := e.unary()[t.Text]
+			if !ok {
+				return 0, &Error{Token: t, Err: ErrUnknownOperator}
+			}
+			args, perr := pop(t, 1)
+			if perr != nil {
+				return 0, perr
+			}
+			v, err = op.Fn(args[0])
+
+		case TokenFunction:
+			fn, ok := e.functions()[t.Text]
+			if !ok {
+				return 0, &Error{Token: t, Err: ErrUnknownFunction}
+			}
+			args, perr := pop(t, fn.Arity)
+			if perr != nil {
+				return 0, perr
+			}
+			v, err = fn.Fn(args)
+
+		default:
+			err = ErrSyntax
+		}
+
+		if err != nil {
+			return 0, &Error{Token: t, Err: err}
+		}
+
+		operands.Push(v)
+	}
+
+	switch operands.Count() {
+	case 0:
+		return 0, ErrEmpty
+	case 1:
+		return operands.Pop(), nil
+	default:
+		return 0, &Error{Token: tokens[len(tokens)-1], Err: ErrExtraOperand}
+	}
+}
+
+// binary returns the evaluator's binary operator table.
+func (e *Evaluator) binary() map[string]BinaryOp {
+	if e.Binary == nil {
+		return defaultBinary
+	}
+
+	return e.Binary
+}
+
+// unary returns the evaluator's unary operator table.
+func (e *Evaluator) unary() map[string]UnaryOp {
+	if e.Unary == nil {
+		return defaultUnary
+	}
+
+	return e.Unary
+}
+
+// functions returns the evaluator's function table.
+func (e *Evaluator) functions() map[string]Function {
+	if e.Functions == nil {
+		return defaultFunctions
+	}
+
+	return e.Functions
+}
+
+// constants returns the evaluator's constant table.
+func (e *Evaluator) constants() map[string]float64 {
+	if e.Constants == nil {
+		return defaultConstants
+	}
+
+	return e.Constants
+}
@@ -0,0 +1,54 @@
+package rpn_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/green-aloe/utilities/rpn"
+)
+
+func ExampleEval() {
+	v, err := rpn.Eval("2 * (x + 1) ^ 2", map[string]float64{"x": 2})
+	fmt.Println(v, err)
+
+	// Output:
+	// 18 <nil>
+}
+
+func ExampleEvalRPN() {
+	v, err := rpn.EvalRPN("3 4 + 2 *", nil)
+	fmt.Println(v, err)
+
+	// Output:
+	// 14 <nil>
+}
+
+func ExampleError() {
+	_, err := rpn.Eval("1 + max(2)", nil)
+
+	var rerr *rpn.Error
+	if errors.As(err, &rerr) {
+		fmt.Println(rerr.Token.Pos, rerr.Token.Text)
+	}
+	fmt.Println(errors.Is(err, rpn.ErrArity))
+
+	// Output:
+	// 4 max
+	// true
+}
+
+func ExampleNew() {
+	e := rpn.New()
+	e.Functions["avg"] = rpn.Function{
+		Arity: 2,
+		Fn: func(args []float64) (float64, error) {
+			return (args[0] + args[1]) / 2, nil
+		},
+	}
+
+	v, err := e.Eval("avg(1, 4)", nil)
+	fmt.Println(v, err)
+
+	// Output:
+	// 2.5 <nil>
+}
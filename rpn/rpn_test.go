@@ -0,0 +1,230 @@
+package rpn
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_EvalRPN tests that EvalRPN evaluates RPN expressions with the built-in tables.
+func Test_EvalRPN(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		vars := map[string]float64{"x": 3, "y": 4}
+		for _, test := range []struct {
+			expr string
+			want float64
+		}{
+			{"42", 42},
+			{"3 4 +", 7},
+			{"3 4 + 2 *", 14},
+			{"10 4 - 3 -", 3},
+			{"10 4 3 - -", 9},
+			{"2 3 ^", 8},
+			{"x x * y y * + sqrt", 5},
+			{"1 2 max 3 min", 2},
+			{"pi", math.Pi},
+			{"-4", -4},
+			{"3 -4 +", -1},
+			{"3 +4 -", -1},
+			{"-2.5 2 *", -5},
+			{"1 -.5e1 -", 6},
+			{"10 4 - -3 *", -18},
+			{"x -1 *", -3},
+		} {
+			v, err := EvalRPN(test.expr, vars)
+			require.NoError(t, err, test.expr)
+			require.InDelta(t, test.want, v, 1e-9, test.expr)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, test := range []struct {
+			expr string
+			err  error
+			want Token
+		}{
+			{"+", ErrMissingOperand, Token{TokenOperator, "+", 0}},
+			{"1 +", ErrMissingOperand, Token{TokenOperator, "+", 2}},
+			{"1 max", ErrMissingOperand, Token{TokenFunction, "max", 2}},
+			{"1 2", ErrExtraOperand, Token{TokenNumber, "2", 2}},
+			{"1 2 3 +", ErrExtraOperand, Token{TokenOperator, "+", 6}},
+			{"z 1 +", ErrUnknownVariable, Token{TokenIdent, "z", 0}},
+			{"1 0 /", ErrDivisionByZero, Token{TokenOperator, "/", 4}},
+			{"( 1 )", ErrSyntax, Token{TokenLeftParen, "(", 0}},
+			{"1 , 2", ErrSyntax, Token{TokenComma, ",", 2}},
+			{"1 # 2", ErrSyntax, Token{TokenOperator, "#", 2}},
+			{"3 4 -5", ErrExtraOperand, Token{TokenNumber, "-5", 4}},
+			{"- 4", ErrMissingOperand, Token{TokenOperator, "-", 0}},
+		} {
+			_, err := EvalRPN(test.expr, nil)
+			require.ErrorIs(t, err, test.err, test.expr)
+
+			var rerr *Error
+			require.True(t, errors.As(err, &rerr), test.expr)
+			require.Equal(t, test.want, rerr.Token, test.expr)
+		}
+
+		_, err := EvalRPN("", nil)
+		require.ErrorIs(t, err, ErrEmpty)
+	})
+}
+
+// Test_Evaluator_Evaluate tests that Evaluator's Evaluate method rejects tokens it can't evaluate.
+func Test_Evaluator_Evaluate(t *testing.T) {
+	var e Evaluator
+	for _, test := range []struct {
+		token Token
+		err   error
+	}{
+		{Token{TokenNumber, "1x", 0}, ErrSyntax},
+		{Token{TokenOperator, "@", 0}, ErrUnknownOperator},
+		{Token{TokenUnary, "@", 0}, ErrUnknownOperator},
+		{Token{TokenFunction, "nope", 0}, ErrUnknownFunction},
+		{Token{TokenLeftParen, "(", 0}, ErrSyntax},
+	} {
+		_, err := e.Evaluate([]Token{test.token}, nil)
+		require.ErrorIs(t, err, test.err, test.token)
+
+		var rerr *Error
+		require.True(t, errors.As(err, &rerr))
+		require.Equal(t, test.token, rerr.Token)
+	}
+
+	_, err := e.Evaluate(nil, nil)
+	require.ErrorIs(t, err, ErrEmpty)
+}
+
+// Test_Evaluator_Tables tests that an evaluator uses its own operator, function, and constant
+// tables in place of the built-in ones.
+func Test_Evaluator_Tables(t *testing.T) {
+	t.Run("custom", func(t *testing.T) {
+		e := New()
+		e.Binary["**"] = e.Binary["^"]
+		e.Binary["&"] = BinaryOp{Precedence: 0, Fn: func(a, b float64) (float64, error) {
+			return math.Min(a, b), nil
+		}}
+		e.Unary["!"] = UnaryOp{Precedence: 3, Fn: func(a float64) (float64, error) {
+			if a == 0 {
+				return 1, nil
+			}
+			return 0, nil
+		}}
+		e.Functions["hyp"] = Function{Arity: 2, Fn: func(args []float64) (float64, error) {
+			return math.Hypot(args[0], args[1]), nil
+		}}
+		e.Functions["zero"] = Function{Arity: 0, Fn: func([]float64) (float64, error) { return 0, nil }}
+		e.Functions["sub"] = Function{Arity: 2, Fn: func(args []float64) (float64, error) {
+			return args[0] - args[1], nil
+		}}
+		e.Constants["answer"] = 42
+		delete(e.Functions, "sqrt")
+
+		for _, test := range []struct {
+			expr string
+			want float64
+		}{
+			{"2 ** 3 ** 2", 512},
+			{"1 + 5 & 2 * 2", 4},
+			{"!zero() + !1", 1},
+			{"hyp(3, 4)", 5},
+			{"sub(10, 3)", 7},
+			{"answer", 42},
+		} {
+			v, err := e.Eval(test.expr, nil)
+			require.NoError(t, err, test.expr)
+			require.Equal(t, test.want, v, test.expr)
+		}
+
+		v, err := e.EvalRPN("3 4 hyp ! 10 3 sub +", nil)
+		require.NoError(t, err)
+		require.Equal(t, 7.0, v)
+
+		_, err = e.Eval("sqrt(4)", nil)
+		require.ErrorIs(t, err, ErrUnknownFunction)
+
+		// The built-in tables are unaffected.
+		_, err = Eval("2 ** 3", nil)
+		require.ErrorIs(t, err, ErrMissingOperand)
+		v, err = Eval("sqrt(4)", nil)
+		require.NoError(t, err)
+		require.Equal(t, 2.0, v)
+	})
+
+	t.Run("unary after operand", func(t *testing.T) {
+		e := New()
+		e.Unary["!"] = UnaryOp{Precedence: 3, Fn: func(a float64) (float64, error) { return a, nil }}
+
+		_, err := e.Eval("1 ! 2", nil)
+		require.ErrorIs(t, err, ErrSyntax)
+
+		_, err = e.Eval("!", nil)
+		require.ErrorIs(t, err, ErrMissingOperand)
+	})
+
+	t.Run("empty tables", func(t *testing.T) {
+		e := Evaluator{
+			Binary:    map[string]BinaryOp{},
+			Unary:     map[string]UnaryOp{},
+			Functions: map[string]Function{},
+			Constants: map[string]float64{},
+		}
+
+		_, err := e.Eval("1 + 2", nil)
+		require.ErrorIs(t, err, ErrSyntax)
+		_, err = e.Eval("pi", nil)
+		require.ErrorIs(t, err, ErrUnknownVariable)
+		_, err = e.Eval("sqrt(1)", nil)
+		require.ErrorIs(t, err, ErrUnknownFunction)
+
+		v, err := e.Eval("(x)", map[string]float64{"x": 1})
+		require.NoError(t, err)
+		require.Equal(t, 1.0, v)
+	})
+
+	t.Run("function error", func(t *testing.T) {
+		errDomain := errors.New("domain")
+		e := New()
+		e.Functions["sqrt"] = Function{Arity: 1, Fn: func(args []float64) (float64, error) {
+			if args[0] < 0 {
+				return 0, errDomain
+			}
+			return math.Sqrt(args[0]), nil
+		}}
+
+		_, err := e.Eval("1 + sqrt(-1)", nil)
+		require.ErrorIs(t, err, errDomain)
+
+		var rerr *Error
+		require.True(t, errors.As(err, &rerr))
+		require.Equal(t, Token{TokenFunction, "sqrt", 4}, rerr.Token)
+		require.Equal(t, `domain: "sqrt" at offset 4`, err.Error())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		e := New()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				v, err := e.Eval("x * 2 + 1", map[string]float64{"x": float64(i)})
+				require.NoError(t, err)
+				require.Equal(t, float64(i*2+1), v)
+			}(i)
+		}
+		wg.Wait()
+	})
+}
+
+// Test_Error tests that Error describes the offending token.
+func Test_Error(t *testing.T) {
+	_, err := Eval("1 + y", nil)
+	require.EqualError(t, err, `rpn: unknown variable: "y" at offset 4`)
+
+	_, err = Eval("1 +", nil)
+	require.EqualError(t, err, "rpn: missing operand at end of expression")
+}
@@ -0,0 +1,175 @@
+package rpn
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A TokenKind identifies what a token is.
+type TokenKind int
+
+const (
+	// TokenNumber is a numeric literal, such as 42, 2.5, .5, or 1e-3.
+	TokenNumber TokenKind = iota
+	// TokenIdent is a name, made of letters, digits, and underscores and not starting with a digit.
+	// In an expression, it names a variable, a constant, or a function.
+	TokenIdent
+	// TokenOperator is a run of symbols naming an operator. Tokenize always produces binary
+	// operators; Infix marks operators in prefix position as TokenUnary.
+	TokenOperator
+	// TokenUnary is an operator that takes a single operand.
+	TokenUnary
+	// TokenFunction is a TokenIdent that names a function.
+	TokenFunction
+	// TokenLeftParen is an opening parenthesis.
+	TokenLeftParen
+	// TokenRightParen is a closing parenthesis.
+	TokenRightParen
+	// TokenComma separates a function's arguments.
+	TokenComma
+	// TokenEnd marks the end of an expression. It only appears in errors.
+	TokenEnd
+)
+
+// String returns a description of the token kind.
+func (k TokenKind) String() string {
+	switch k {
+	case TokenNumber:
+		return "number"
+	case TokenIdent:
+		return "identifier"
+	case TokenOperator:
+		return "operator"
+	case TokenUnary:
+		return "unary operator"
+	case TokenFunction:
+		return "function"
+	case TokenLeftParen:
+		return "left parenthesis"
+	case TokenRightParen:
+		return "right parenthesis"
+	case TokenComma:
+		return "comma"
+	case TokenEnd:
+		return "end of expression"
+	default:
+		return "TokenKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// A Token is one element of an expression.
+type Token struct {
+	Kind TokenKind
+	// Text is the token as it appears in the expression.
+	Text string
+	// Pos is the byte offset of the token in the expression.
+	Pos int
+}
+
+// Tokenize splits an expression into tokens. Whitespace separates tokens but is otherwise ignored.
+// Operators are matched against the evaluator's operator tables, preferring the longest match, so
+// an operator table can contain multi-character operators such as "**" or "<=". If the expression
+// contains anything that isn't a valid token, this returns an *Error wrapping ErrSyntax.
+func (e *Evaluator) Tokenize(expr string) ([]Token, error) {
+	var tokens []Token
+	for pos := 0; pos < len(expr); {
+		r, size := utf8.DecodeRuneInString(expr[pos:])
+
+		var kind TokenKind
+		var n int
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+			continue
+
+		case r == '(':
+			kind, n = TokenLeftParen, 1
+		case r == ')':
+			kind, n = TokenRightParen, 1
+		case r == ',':
+			kind, n = TokenComma, 1
+
+		case isDigit(r) || (r == '.' && pos+1 < len(expr) && isDigit(rune(expr[pos+1]))):
+			kind, n = TokenNumber, scanNumber(expr[pos:])
+			if _, err := strconv.ParseFloat(expr[pos:pos+n], 64); err != nil {
+				return nil, &Error{Token: Token{TokenNumber, expr[pos : pos+n], pos}, Err: ErrSyntax}
+			}
+
+		case r == '_' || unicode.IsLetter(r):
+			kind, n = TokenIdent, scanIdent(expr[pos:])
+
+		default:
+			kind, n = TokenOperator, e.scanOperator(expr[pos:])
+			if n == 0 {
+				return nil, &Error{Token: Token{TokenOperator, string(r), pos}, Err: ErrSyntax}
+			}
+		}
+
+		tokens = append(tokens, Token{Kind: kind, Text: expr[pos : pos+n], Pos: pos})
+		pos += n
+	}
+
+	return tokens, nil
+}
+
+// scanNumber returns the length of the numeric literal at the start of s.
+func scanNumber(s string) int {
+	n := 0
+	digits := func() {
+		for n < len(s) && isDigit(rune(s[n])) {
+			n++
+		}
+	}
+
+	digits()
+	if n < len(s) && s[n] == '.' {
+		n++
+		digits()
+	}
+
+	// An exponent only counts if it has digits, so "2e" is the number 2 followed by the name e.
+	if n < len(s) && (s[n] == 'e' || s[n] == 'E') {
+		m := n + 1
+		if m < len(s) && (s[m] == '+' || s[m] == '-') {
+			m++
+		}
+		if m < len(s) && isDigit(rune(s[m])) {
+			n = m
+			digits()
+		}
+	}
+
+	return n
+}
+
+// scanIdent returns the length of the name at the start of s.
+func scanIdent(s string) int {
+	return len(s) - len(strings.TrimLeftFunc(s, func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}))
+}
+
+// scanOperator returns the length of the longest operator in the evaluator's tables that s starts
+// with, or 0 if there isn't one.
+func (e *Evaluator) scanOperator(s string) int {
+	var n int
+	for op := range e.binary() {
+		if len(op) > n && strings.HasPrefix(s, op) {
+			n = len(op)
+		}
+	}
+	for op := range e.unary() {
+		if len(op) > n && strings.HasPrefix(s, op) {
+			n = len(op)
+		}
+	}
+
+	return n
+}
+
+// isDigit returns true if r is an ASCII digit.
+func isDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
@@ -0,0 +1,90 @@
+package rpn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Evaluator_Tokenize tests that Evaluator's Tokenize method splits expressions into tokens.
+func Test_Evaluator_Tokenize(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, test := range []struct {
+			expr string
+			want []Token
+		}{
+			{"", nil},
+			{"   ", nil},
+			{"42", []Token{{TokenNumber, "42", 0}}},
+			{" 2.5 .5 1e3 1E-3 2.e+2", []Token{
+				{TokenNumber, "2.5", 1},
+				{TokenNumber, ".5", 5},
+				{TokenNumber, "1e3", 8},
+				{TokenNumber, "1E-3", 12},
+				{TokenNumber, "2.e+2", 17},
+			}},
+			{"2e", []Token{{TokenNumber, "2", 0}, {TokenIdent, "e", 1}}},
+			{"x_1 _y café", []Token{{TokenIdent, "x_1", 0}, {TokenIdent, "_y", 4}, {TokenIdent, "café", 7}}},
+			{"max(a,-b)*2", []Token{
+				{TokenIdent, "max", 0},
+				{TokenLeftParen, "(", 3},
+				{TokenIdent, "a", 4},
+				{TokenComma, ",", 5},
+				{TokenOperator, "-", 6},
+				{TokenIdent, "b", 7},
+				{TokenRightParen, ")", 8},
+				{TokenOperator, "*", 9},
+				{TokenNumber, "2", 10},
+			}},
+			{"1--2", []Token{{TokenNumber, "1", 0}, {TokenOperator, "-", 1}, {TokenOperator, "-", 2}, {TokenNumber, "2", 3}}},
+		} {
+			var e Evaluator
+			tokens, err := e.Tokenize(test.expr)
+			require.NoError(t, err, test.expr)
+			require.Equal(t, test.want, tokens, test.expr)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, test := range []struct {
+			expr string
+			want Token
+		}{
+			{"1 $ 2", Token{TokenOperator, "$", 2}},
+			{"1 . 2", Token{TokenOperator, ".", 2}},
+		} {
+			var e Evaluator
+			_, err := e.Tokenize(test.expr)
+			require.ErrorIs(t, err, ErrSyntax, test.expr)
+
+			var rerr *Error
+			require.True(t, errors.As(err, &rerr))
+			require.Equal(t, test.want, rerr.Token, test.expr)
+		}
+	})
+
+	t.Run("longest operator", func(t *testing.T) {
+		e := New()
+		e.Binary["**"] = e.Binary["^"]
+		e.Binary["<="] = BinaryOp{}
+		e.Unary["!"] = UnaryOp{}
+
+		tokens, err := e.Tokenize("a**b<=!c*d")
+		require.NoError(t, err)
+
+		var texts []string
+		for _, tok := range tokens {
+			texts = append(texts, tok.Text)
+		}
+		require.Equal(t, []string{"a", "**", "b", "<=", "!", "c", "*", "d"}, texts)
+	})
+}
+
+// Test_TokenKind_String tests that every token kind has a description.
+func Test_TokenKind_String(t *testing.T) {
+	for k := TokenNumber; k <= TokenEnd; k++ {
+		require.NotContains(t, k.String(), "TokenKind")
+	}
+	require.Equal(t, "TokenKind(99)", TokenKind(99).String())
+}
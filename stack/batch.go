@@ -0,0 +1,74 @@
+package stack
+
+import "context"
+
+// PushMany adds values to the top of the stack in order, so the last value ends up on top. All of
+// the values are pushed under a single lock acquisition, so no other goroutine can observe or
+// interleave with a partial batch. If any goroutines are blocked in PopWait, the first values are
+// handed to them, oldest waiter first, exactly as if the values had been pushed one at a time.
+//
+// If the stack is bounded and the values don't fit, the overflow policy applies to the whole batch:
+// OverflowReject rejects every value with ErrFull, OverflowBlock waits until they all fit (or
+// returns ErrFull if the batch is larger than the stack's capacity), and OverflowEvict discards
+// values from the bottom of the stack to make room. If the stack is closed, this returns ErrClosed
+// and pushes nothing.
+func (s *Stack[T]) PushMany(vs ...T) error {
+	_, err := s.push(context.Background(), vs, true)
+	return err
+}
+
+// PopN removes up to n values from the top of the stack and returns them in the order they were
+// popped, so the value that was on top is first. If the stack has fewer than n values, this
+// returns all of them. If the stack is empty or n is not positive, this returns nil.
+func (s *Stack[T]) PopN(n int) []T {
+	if s == nil || n <= 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	n = min(n, len(s.items))
+	if n == 0 {
+		return nil
+	}
+
+	popped := make([]T, n)
+	for i := range popped {
+		popped[i] = s.items[len(s.items)-1-i]
+	}
+	clear(s.items[len(s.items)-n:])
+	s.items = s.items[:len(s.items)-n]
+	s.notify(EventPopped, popped...)
+	s.stats.popped(n)
+	s.removed()
+
+	return popped
+}
+
+// Drain removes every value from the stack and returns them in the order they would have been
+// popped, so the value that was on top is first. The stack's storage is handed to the caller
+// rather than copied. If the stack is empty, this returns nil.
+func (s *Stack[T]) Drain() []T {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	drained := s.items
+	s.items = nil
+	if len(drained) == 0 {
+		return nil
+	}
+	s.removed()
+
+	for i, j := 0, len(drained)-1; i < j; i, j = i+1, j-1 {
+		drained[i], drained[j] = drained[j], drained[i]
+	}
+	s.notify(EventPopped, drained...)
+	s.stats.popped(len(drained))
+
+	return drained
+}
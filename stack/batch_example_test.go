@@ -0,0 +1,43 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleStack_PushMany() {
+	var s stack.Stack[string]
+	s.PushMany("a", "b", "c")
+
+	fmt.Println(s.Count(), s.Peek())
+
+	// Output:
+	// 3 c
+}
+
+func ExampleStack_PopN() {
+	var s stack.Stack[int]
+	s.PushMany(1, 2, 3, 4, 5)
+
+	fmt.Println(s.PopN(2))
+	fmt.Println(s.PopN(5))
+	fmt.Println(s.PopN(1))
+
+	// Output:
+	// [5 4]
+	// [3 2 1]
+	// []
+}
+
+func ExampleStack_Drain() {
+	var s stack.Stack[rune]
+	for _, r := range "abc" {
+		s.Push(r)
+	}
+
+	fmt.Println(string(s.Drain()), s.Count())
+
+	// Output:
+	// cba 0
+}
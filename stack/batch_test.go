@@ -0,0 +1,286 @@
+package stack
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Stack_PushMany tests that Stack's PushMany method adds values to the top of the stack as a
+// single operation for various stack configurations.
+func Test_Stack_PushMany(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NoError(t, s.PushMany(1, 2, 3))
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("no values", func(t *testing.T) {
+		var s Stack[int]
+		require.NoError(t, s.PushMany())
+		require.True(t, s.Empty())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		require.NoError(t, s.PushMany(1, 2, 3))
+		require.Equal(t, 3, s.Count())
+		require.Equal(t, 3, s.Pop())
+		require.Equal(t, 2, s.Pop())
+		require.Equal(t, 1, s.Pop())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.Push("a")
+		require.NoError(t, s.PushMany("b", "c"))
+		require.Equal(t, []string{"c", "b", "a"}, s.Drain())
+	})
+
+	t.Run("closed stack", func(t *testing.T) {
+		var s Stack[string]
+		s.Close()
+		require.ErrorIs(t, s.PushMany("a", "b"), ErrClosed)
+		require.True(t, s.Empty())
+	})
+
+	t.Run("waiters", func(t *testing.T) {
+		var s Stack[int]
+
+		ch := make(chan int, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				top, err := s.PopWait(context.Background())
+				require.NoError(t, err)
+				ch <- top
+			}()
+		}
+		awaitWaiters(t, &s, 2)
+
+		require.NoError(t, s.PushMany(1, 2, 3, 4))
+		have := []int{<-ch, <-ch}
+		sort.Ints(have)
+		require.Equal(t, []int{1, 2}, have)
+		require.Equal(t, []int{4, 3}, s.Drain())
+	})
+
+	t.Run("bounded stack, reject", func(t *testing.T) {
+		s := NewBounded[int](3, OverflowReject)
+		require.NoError(t, s.PushMany(1, 2))
+		require.ErrorIs(t, s.PushMany(3, 4), ErrFull)
+		require.Equal(t, 2, s.Count())
+		require.NoError(t, s.PushMany(3))
+		require.Equal(t, []int{3, 2, 1}, s.Drain())
+	})
+
+	t.Run("bounded stack, evict", func(t *testing.T) {
+		s := NewBounded[int](3, OverflowEvict)
+		require.NoError(t, s.PushMany(1, 2))
+		require.NoError(t, s.PushMany(3, 4))
+		require.Equal(t, []int{4, 3, 2}, s.Drain())
+
+		require.NoError(t, s.PushMany(1, 2, 3, 4, 5))
+		require.Equal(t, []int{5, 4, 3}, s.Drain())
+	})
+
+	t.Run("bounded stack, block", func(t *testing.T) {
+		s := NewBounded[int](3, OverflowBlock)
+		require.NoError(t, s.PushMany(1, 2))
+		require.ErrorIs(t, s.PushMany(1, 2, 3, 4), ErrFull)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			require.NoError(t, s.PushMany(3, 4))
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("push did not block")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		require.Equal(t, 2, s.Pop())
+		<-done
+		require.Equal(t, []int{4, 3, 1}, s.Drain())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				require.NoError(t, s.PushMany(i, i, i))
+			}(i)
+		}
+		wg.Wait()
+
+		// Each batch must have landed contiguously.
+		drained := s.Drain()
+		require.Len(t, drained, 300)
+		for i := 0; i < len(drained); i += 3 {
+			require.Equal(t, drained[i], drained[i+1])
+			require.Equal(t, drained[i], drained[i+2])
+		}
+	})
+}
+
+// Test_Stack_PopN tests that Stack's PopN method removes and returns up to n values from the top of
+// the stack for various stack configurations.
+func Test_Stack_PopN(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.Nil(t, s.PopN(3))
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		require.Nil(t, s.PopN(3))
+	})
+
+	t.Run("non-positive n", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+		require.Nil(t, s.PopN(0))
+		require.Nil(t, s.PopN(-1))
+		require.Equal(t, 3, s.Count())
+	})
+
+	t.Run("fewer than n", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+		require.Equal(t, []int{3, 2, 1}, s.PopN(5))
+		require.True(t, s.Empty())
+	})
+
+	t.Run("more than n", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3, 4, 5)
+
+		popped := s.PopN(2)
+		require.Equal(t, []int{5, 4}, popped)
+		require.Equal(t, 2, cap(popped))
+		require.Equal(t, 3, s.Count())
+		require.Equal(t, 3, s.Peek())
+	})
+
+	t.Run("wakes blocked push", func(t *testing.T) {
+		s := NewBounded[int](2, OverflowBlock)
+		s.PushMany(1, 2)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			require.NoError(t, s.PushMany(3, 4))
+		}()
+		awaitBlockedPush(t, s)
+
+		require.Equal(t, []int{2, 1}, s.PopN(2))
+		<-done
+		require.Equal(t, []int{4, 3}, s.PopN(2))
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+		for i := 0; i < 300; i++ {
+			s.Push(i)
+		}
+
+		ch := make(chan []int, 100)
+		for i := 0; i < 100; i++ {
+			go func() {
+				ch <- s.PopN(3)
+			}()
+		}
+
+		var have []int
+		for i := 0; i < 100; i++ {
+			popped := <-ch
+			require.Len(t, popped, 3)
+			require.Equal(t, popped[0]-1, popped[1])
+			require.Equal(t, popped[1]-1, popped[2])
+			have = append(have, popped...)
+		}
+		sort.Ints(have)
+		for i, v := range have {
+			require.Equal(t, i, v)
+		}
+		require.True(t, s.Empty())
+	})
+}
+
+// Test_Stack_Drain tests that Stack's Drain method removes and returns every value on the stack for
+// various stack configurations.
+func Test_Stack_Drain(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.Nil(t, s.Drain())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		require.Nil(t, s.Drain())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b", "c", "d")
+		require.Equal(t, []string{"d", "c", "b", "a"}, s.Drain())
+		require.True(t, s.Empty())
+		require.Nil(t, s.Drain())
+
+		s.Push("e")
+		require.Equal(t, []string{"e"}, s.Drain())
+	})
+
+	t.Run("wakes blocked push", func(t *testing.T) {
+		s := NewBounded[int](1, OverflowBlock)
+		s.Push(1)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.Push(2)
+		}()
+		awaitBlockedPush(t, s)
+
+		require.Equal(t, []int{1}, s.Drain())
+		<-done
+		require.Equal(t, []int{2}, s.Drain())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+
+		var mutex sync.Mutex
+		var have []int
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.Push(i)
+				drained := s.Drain()
+
+				mutex.Lock()
+				have = append(have, drained...)
+				mutex.Unlock()
+			}(i)
+		}
+		wg.Wait()
+
+		sort.Ints(have)
+		for i, v := range have {
+			require.Equal(t, i, v)
+		}
+		require.Len(t, have, 100)
+	})
+}
@@ -0,0 +1,46 @@
+package stack
+
+// Snapshot returns a copy of the stack's values from bottom to top, so the last element is the top
+// of the stack. The copy is taken under a single lock acquisition, so it is a consistent
+// point-in-time view even while other goroutines use the stack. If the stack is nil, this returns
+// nil.
+func (s *Stack[T]) Snapshot() []T {
+	return s.snapshot()
+}
+
+// Clone returns a new stack holding a copy of the stack's values. The clone has the same capacity,
+// overflow policy, and shrink policy as the original, but it starts out open, with no goroutines
+// waiting on it, regardless of the original's state. Values are copied as they are, so if they
+// contain pointers, the clone and the original share what those pointers point to; use CloneFunc to
+// copy them deeply. If the stack is nil, this returns nil.
+func (s *Stack[T]) Clone() *Stack[T] {
+	return s.CloneFunc(nil)
+}
+
+// CloneFunc is like Clone, but it copies each value with clone, which is called on every value
+// from bottom to top while the stack is locked and so must not use the stack. If clone is nil,
+// values are copied as they are.
+func (s *Stack[T]) CloneFunc(clone func(T) T) *Stack[T] {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	items := make([]T, len(s.items))
+	if clone == nil {
+		copy(items, s.items)
+	} else {
+		for i, v := range s.items {
+			items[i] = clone(v)
+		}
+	}
+
+	return &Stack[T]{
+		items:        items,
+		capacity:     s.capacity,
+		policy:       s.policy,
+		shrinkPolicy: s.shrinkPolicy,
+	}
+}
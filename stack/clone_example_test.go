@@ -0,0 +1,45 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleStack_Snapshot() {
+	var s stack.Stack[string]
+	s.PushMany("a", "b", "c")
+
+	fmt.Println(s.Snapshot())
+
+	// Output:
+	// [a b c]
+}
+
+func ExampleStack_Clone() {
+	var s stack.Stack[int]
+	s.PushMany(1, 2, 3)
+
+	c := s.Clone()
+	c.Pop()
+
+	fmt.Println(s.Count(), c.Count())
+
+	// Output:
+	// 3 2
+}
+
+func ExampleStack_CloneFunc() {
+	var s stack.Stack[[]string]
+	s.Push([]string{"a", "b"})
+
+	c := s.CloneFunc(func(v []string) []string {
+		return append([]string(nil), v...)
+	})
+	c.Peek()[0] = "z"
+
+	fmt.Println(s.Peek(), c.Peek())
+
+	// Output:
+	// [a b] [z b]
+}
@@ -0,0 +1,151 @@
+package stack
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Stack_Snapshot tests that Stack's Snapshot method returns a copy of the stack's values for
+// various stack configurations.
+func Test_Stack_Snapshot(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.Nil(t, s.Snapshot())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		require.Empty(t, s.Snapshot())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b", "c")
+
+		snapshot := s.Snapshot()
+		require.Equal(t, []string{"a", "b", "c"}, snapshot)
+
+		snapshot[2] = "z"
+		require.Equal(t, "c", s.Peek())
+
+		s.Pop()
+		require.Len(t, snapshot, 3)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				s.PushMany(i, i)
+			}(i)
+			go func() {
+				defer wg.Done()
+
+				// Pairs are pushed atomically, so a snapshot never splits one.
+				snapshot := s.Snapshot()
+				require.Zero(t, len(snapshot)%2)
+				for j := 0; j < len(snapshot); j += 2 {
+					require.Equal(t, snapshot[j], snapshot[j+1])
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+// Test_Stack_Clone tests that Stack's Clone method returns an independent copy of the stack for
+// various stack configurations.
+func Test_Stack_Clone(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.Nil(t, s.Clone())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		c := s.Clone()
+		require.NotNil(t, c)
+		require.True(t, c.Empty())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+
+		c := s.Clone()
+		c.Push(4)
+		s.Pop()
+
+		require.Equal(t, []int{4, 3, 2, 1}, c.Drain())
+		require.Equal(t, []int{2, 1}, s.Drain())
+	})
+
+	t.Run("configuration", func(t *testing.T) {
+		s := NewBounded[int](2, OverflowReject)
+		s.SetShrinkPolicy(ShrinkQuarter)
+		s.PushMany(1, 2)
+		s.Close()
+
+		c := s.Clone()
+		require.False(t, c.Closed())
+		require.ErrorIs(t, c.TryPush(3), ErrFull)
+		require.NotNil(t, c.shrinkPolicy)
+		require.Equal(t, []int{2, 1}, c.Drain())
+	})
+
+	t.Run("shallow copy", func(t *testing.T) {
+		var s Stack[[]int]
+		s.Push([]int{1, 2})
+
+		c := s.Clone()
+		c.Peek()[0] = 10
+		require.Equal(t, []int{10, 2}, s.Peek())
+	})
+}
+
+// Test_Stack_CloneFunc tests that Stack's CloneFunc method copies each value with the given function
+// for various stack configurations.
+func Test_Stack_CloneFunc(t *testing.T) {
+	clone := func(v []int) []int { return append([]int(nil), v...) }
+
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[[]int]
+		require.Nil(t, s.CloneFunc(clone))
+	})
+
+	t.Run("nil function", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2)
+		require.Equal(t, []int{2, 1}, s.CloneFunc(nil).Drain())
+	})
+
+	t.Run("deep copy", func(t *testing.T) {
+		var s Stack[[]int]
+		s.Push([]int{1, 2})
+		s.Push([]int{3, 4})
+
+		c := s.CloneFunc(clone)
+		c.Peek()[0] = 30
+		require.Equal(t, []int{3, 4}, s.Peek())
+		require.Equal(t, []int{30, 4}, c.Peek())
+	})
+
+	t.Run("order", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+
+		var seen []int
+		c := s.CloneFunc(func(v int) int {
+			seen = append(seen, v)
+			return v * 10
+		})
+		require.Equal(t, []int{1, 2, 3}, seen)
+		require.Equal(t, []int{30, 20, 10}, c.Drain())
+	})
+}
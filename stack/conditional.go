@@ -0,0 +1,35 @@
+package stack
+
+// PopIf removes and returns the value at the top of the stack if pred returns true for it. The
+// check and the removal happen under a single lock acquisition, so no other goroutine can change
+// the top of the stack in between. pred is called while the stack is locked and so must not use the
+// stack. If the stack is empty, pred is nil, or pred returns false, the stack is left unchanged and
+// this returns the zero value of the stack's type and false.
+func (s *Stack[T]) PopIf(pred func(T) bool) (t T, ok bool) {
+	if s == nil || pred == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.items) == 0 || !pred(s.items[len(s.items)-1]) {
+		return
+	}
+
+	return s.pop()
+}
+
+// CompareAndPop removes the value at the top of the stack if eq reports that it is equal to
+// expected, and returns true if it did. Like PopIf, the comparison and the removal are atomic, and
+// eq must not use the stack. eq is called with the top of the stack as a and expected as b. If the
+// stack is empty or eq is nil, this returns false.
+func (s *Stack[T]) CompareAndPop(expected T, eq func(a, b T) bool) bool {
+	if eq == nil {
+		return false
+	}
+
+	_, ok := s.PopIf(func(top T) bool { return eq(top, expected) })
+
+	return ok
+}
@@ -0,0 +1,43 @@
+package stack_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleStack_PopIf() {
+	var s stack.Stack[string]
+	s.PushMany("job:1", "urgent:2")
+
+	urgent := func(v string) bool { return strings.HasPrefix(v, "urgent:") }
+
+	top1, ok1 := s.PopIf(urgent)
+	top2, ok2 := s.PopIf(urgent)
+
+	fmt.Println(top1, ok1)
+	fmt.Printf("%q %v\n", top2, ok2)
+	fmt.Println(s.Count())
+
+	// Output:
+	// urgent:2 true
+	// "" false
+	// 1
+}
+
+func ExampleStack_CompareAndPop() {
+	var s stack.Stack[string]
+	s.PushMany("a", "b")
+
+	eq := func(a, b string) bool { return a == b }
+
+	fmt.Println(s.CompareAndPop("a", eq))
+	fmt.Println(s.CompareAndPop("b", eq))
+	fmt.Println(s.Peek())
+
+	// Output:
+	// false
+	// true
+	// a
+}
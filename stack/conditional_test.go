@@ -0,0 +1,170 @@
+package stack
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Stack_PopIf tests that Stack's PopIf method only removes the top value when it satisfies the
+// predicate for various stack configurations.
+func Test_Stack_PopIf(t *testing.T) {
+	even := func(v int) bool { return v%2 == 0 }
+
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		top, ok := s.PopIf(even)
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		top, ok := s.PopIf(func(int) bool {
+			t.Fatal("predicate called on empty stack")
+			return true
+		})
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+
+	t.Run("nil predicate", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(2)
+		top, ok := s.PopIf(nil)
+		require.Zero(t, top)
+		require.False(t, ok)
+		require.Equal(t, 1, s.Count())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2)
+
+		top, ok := s.PopIf(even)
+		require.True(t, ok)
+		require.Equal(t, 2, top)
+
+		top, ok = s.PopIf(even)
+		require.False(t, ok)
+		require.Zero(t, top)
+		require.Equal(t, 1, s.Peek())
+	})
+
+	t.Run("wakes blocked push", func(t *testing.T) {
+		s := NewBounded[int](1, OverflowBlock)
+		s.Push(2)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			require.NoError(t, s.PushMany(4))
+		}()
+		awaitBlockedPush(t, s)
+
+		_, ok := s.PopIf(even)
+		require.True(t, ok)
+		<-done
+		require.Equal(t, 4, s.Pop())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+		for i := 0; i < 100; i++ {
+			s.Push(i)
+		}
+
+		// Many workers race to claim only the value 99. Exactly one must win.
+		var wins atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, ok := s.PopIf(func(v int) bool { return v == 99 }); ok {
+					wins.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, int32(1), wins.Load())
+		require.Equal(t, 98, s.Peek())
+	})
+}
+
+// Test_Stack_CompareAndPop tests that Stack's CompareAndPop method only removes the top value when
+// it equals the expected value for various stack configurations.
+func Test_Stack_CompareAndPop(t *testing.T) {
+	eq := func(a, b string) bool { return a == b }
+
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[string]
+		require.False(t, s.CompareAndPop("a", eq))
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[string]
+		require.False(t, s.CompareAndPop("", eq))
+	})
+
+	t.Run("nil comparison", func(t *testing.T) {
+		var s Stack[string]
+		s.Push("a")
+		require.False(t, s.CompareAndPop("a", nil))
+		require.Equal(t, 1, s.Count())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b")
+
+		require.False(t, s.CompareAndPop("a", eq))
+		require.Equal(t, 2, s.Count())
+
+		require.True(t, s.CompareAndPop("b", eq))
+		require.True(t, s.CompareAndPop("a", eq))
+		require.True(t, s.Empty())
+	})
+
+	t.Run("argument order", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+
+		require.True(t, s.CompareAndPop(2, func(a, b int) bool {
+			require.Equal(t, 1, a)
+			require.Equal(t, 2, b)
+			return true
+		}))
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+		for i := 0; i < 1000; i++ {
+			s.Push(i)
+		}
+
+		// Workers race to pop whatever they last saw on top. Each value can only be claimed once,
+		// so the number of successful pops must match the number of values.
+		eq := func(a, b int) bool { return a == b }
+		var pops atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for !s.Empty() {
+					if s.CompareAndPop(s.Peek(), eq) {
+						pops.Add(1)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, int32(1000), pops.Load())
+		require.True(t, s.Empty())
+	})
+}
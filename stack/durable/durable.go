@@ -0,0 +1,622 @@
+// Package durable provides a stack whose contents survive process restarts by recording every
+// change in a write-ahead log.
+package durable
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+// ErrCorrupt is returned when a stack's log is damaged somewhere other than its final record.
+var ErrCorrupt = errors.New("durable: corrupt log")
+
+// DefaultCompactAfter is the number of obsolete records a stack's log may hold before the stack
+// compacts it, for stacks opened without an explicit threshold.
+const DefaultCompactAfter = 4096
+
+// A SyncPolicy determines when a stack flushes its log to stable storage.
+type SyncPolicy int
+
+const (
+	// SyncAlways flushes the log after every change, so a change is durable once the method making
+	// it returns.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval flushes the log in the background, at most Options.SyncInterval after a change.
+	// Changes made within that window can be lost if the machine crashes.
+	SyncInterval
+	// SyncNever leaves flushing the log to the operating system, except when the stack is compacted
+	// or closed or Sync is called.
+	SyncNever
+)
+
+// Options configures a stack opened with Open. The zero value is ready to use.
+type Options[T any] struct {
+	// Sync determines when the log is flushed to stable storage.
+	Sync SyncPolicy
+	// SyncInterval is the longest a change waits to be flushed with SyncInterval. If it is not
+	// positive, it defaults to one second.
+	SyncInterval time.Duration
+
+	// CompactAfter is the number of obsolete records the log may hold before it is rewritten to
+	// contain only the stack's current values. If it is zero, the stack uses DefaultCompactAfter.
+	// If it is negative, the log is only compacted by calling Compact.
+	CompactAfter int
+
+	// Marshal and Unmarshal encode and decode values in the log. They must be set together; if both
+	// are nil, values are encoded with encoding/json.
+	Marshal   func(T) ([]byte, error)
+	Unmarshal func([]byte, *T) error
+}
+
+// A Stack is a first-in-last-out (FILO) data structure, like stack.Stack, that records every
+// change in a log file before applying it. Opening the log again replays it to restore the stack
+// exactly as it was. If the process crashed while writing the final record, that record is
+// discarded. As pops and clears make older records obsolete, the stack periodically rewrites the
+// log to hold only its current values.
+//
+// A stack is safe for concurrent use, but only one stack may use a log file at a time.
+type Stack[T any] struct {
+	items []T
+
+	file *os.File
+	path string
+
+	// offset is the size of the log's valid records, and records is their number.
+	offset  int64
+	records int
+
+	opts Options[T]
+
+	// timer is the pending background flush with SyncInterval, or nil if there isn't one.
+	timer *time.Timer
+
+	// err is the first error encountered by a method that can't return it.
+	err error
+
+	// broken is set when the log is in an unknown state, after which every change fails with it.
+	broken error
+
+	closed bool
+	mutex  sync.Mutex
+}
+
+// Record operations.
+const (
+	opPush byte = iota + 1
+	opPop
+	opClear
+)
+
+// headerSize is the size of a record's header: the length of its body, the CRC-32 checksum of that
+// length, and the body's CRC-32 checksum, all little-endian uint32s. The length has its own
+// checksum so that a damaged length can't pass for a record that runs past the end of the log.
+const headerSize = 12
+
+// Open opens the stack logged at path, creating the log if it doesn't exist, and restores the
+// stack's values by replaying it. A final record that was only partly written is discarded. If any
+// other record is damaged, this returns an error wrapping ErrCorrupt. If only one of opts.Marshal
+// and opts.Unmarshal is set, this returns an error.
+func Open[T any](path string, opts Options[T]) (*Stack[T], error) {
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = time.Second
+	}
+	if opts.CompactAfter == 0 {
+		opts.CompactAfter = DefaultCompactAfter
+	}
+	if (opts.Marshal == nil) != (opts.Unmarshal == nil) {
+		return nil, errors.New("durable: Marshal and Unmarshal must be set together")
+	}
+	if opts.Marshal == nil {
+		opts.Marshal = func(v T) ([]byte, error) { return json.Marshal(v) }
+		opts.Unmarshal = func(data []byte, v *T) error { return json.Unmarshal(data, v) }
+	}
+
+	// A leftover compaction never replaced the log, so the log is still complete.
+	if err := os.Remove(compactPath(path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Stack[T]{
+		file: file,
+		path: path,
+		opts: opts,
+	}
+
+	if err := s.replay(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	s.maybeCompact()
+
+	return s, nil
+}
+
+// replay restores the stack's values from its log and drops any partly written final record.
+func (s *Stack[T]) replay() error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+
+	r := bufio.NewReader(s.file)
+	var header [headerSize]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return err
+		}
+
+		if crc32.ChecksumIEEE(header[:4]) != binary.LittleEndian.Uint32(header[4:8]) {
+			if s.offset+headerSize == size {
+				// The header was torn while it was being written.
+				break
+			}
+			return fmt.Errorf("%w: bad length at offset %d", ErrCorrupt, s.offset)
+		}
+
+		n := int64(binary.LittleEndian.Uint32(header[:4]))
+		if s.offset+headerSize+n > size {
+			// The length is intact, so this is the final record, and its body was never fully
+			// written.
+			break
+		}
+
+		body := make([]byte, n)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return err
+		}
+
+		if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(header[8:]) {
+			if s.offset+headerSize+n == size {
+				// The body was torn while it was being written.
+				break
+			}
+			return fmt.Errorf("%w: bad checksum at offset %d", ErrCorrupt, s.offset)
+		}
+
+		if err := s.apply(body); err != nil {
+			return fmt.Errorf("%w: offset %d: %w", ErrCorrupt, s.offset, err)
+		}
+
+		s.offset += headerSize + n
+		s.records++
+	}
+
+	if s.offset < size {
+		if err := s.file.Truncate(s.offset); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.file.Seek(s.offset, io.SeekStart)
+	return err
+}
+
+// apply applies a record's body to the stack's values.
+func (s *Stack[T]) apply(body []byte) error {
+	if len(body) == 0 {
+		return errors.New("empty record")
+	}
+
+	switch op, payload := body[0], body[1:]; op {
+	case opPush:
+		var v T
+		if err := s.opts.Unmarshal(payload, &v); err != nil {
+			return err
+		}
+		s.items = append(s.items, v)
+
+	case opPop:
+		if len(s.items) == 0 {
+			return errors.New("pop from empty stack")
+		}
+		var zero T
+		s.items[len(s.items)-1] = zero
+		s.items = s.items[:len(s.items)-1]
+
+	case opClear:
+		s.items = nil
+
+	default:
+		return fmt.Errorf("unknown operation %d", op)
+	}
+
+	return nil
+}
+
+// Push adds a value to the top of the stack once it is recorded in the log. If the value can't be
+// recorded, it is not pushed and this returns the error. If the stack is closed, this returns
+// stack.ErrClosed.
+func (s *Stack[T]) Push(v T) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	payload, err := s.opts.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if err := s.log(opPush, payload); err != nil {
+		return err
+	}
+
+	s.items = append(s.items, v)
+	s.maybeCompact()
+
+	return nil
+}
+
+// Pop removes and returns the value at the top of the stack. If the stack is empty, or the pop
+// can't be recorded in the log, this returns the zero value of the stack's type.
+func (s *Stack[T]) Pop() (t T) {
+	t, _ = s.CheckPop()
+	return t
+}
+
+// CheckPop returns the value at the top of the stack and a boolean indicating whether the stack is
+// empty. If the stack is empty, this returns the zero value of the stack's type and false. If the
+// pop can't be recorded in the log, the value stays on the stack, this also returns the zero value
+// and false, and the error is available from Err, unless it is only that the stack is closed.
+func (s *Stack[T]) CheckPop() (t T, ok bool) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.items) == 0 {
+		return
+	}
+
+	if err := s.log(opPop, nil); err != nil {
+		if !errors.Is(err, stack.ErrClosed) {
+			s.setErr(err)
+		}
+		return
+	}
+
+	t, s.items[len(s.items)-1] = s.items[len(s.items)-1], t
+	s.items = s.items[:len(s.items)-1]
+
+	s.maybeCompact()
+
+	return t, true
+}
+
+// Peek returns the value at the top of the stack without removing it. If the stack is empty, this
+// returns the zero value of the stack's type.
+func (s *Stack[T]) Peek() (t T) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.items) == 0 {
+		return
+	}
+
+	return s.items[len(s.items)-1]
+}
+
+// Empty returns true if the stack is empty.
+func (s *Stack[T]) Empty() bool {
+	return s.Count() == 0
+}
+
+// Count returns the number of elements in the stack.
+func (s *Stack[T]) Count() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.items)
+}
+
+// Clear removes all elements from the stack once the change is recorded in the log. If it can't be
+// recorded, the stack is unchanged and this returns the error.
+func (s *Stack[T]) Clear() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.log(opClear, nil); err != nil {
+		return err
+	}
+
+	s.items = nil
+	s.maybeCompact()
+
+	return nil
+}
+
+// Err returns the first error encountered by a pop, a background flush, or an automatic
+// compaction, none of which can return their errors directly, or nil if there hasn't been one.
+func (s *Stack[T]) Err() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.err
+}
+
+// Sync flushes the log to stable storage, making every change so far durable regardless of the
+// stack's sync policy.
+func (s *Stack[T]) Sync() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.usable(); err != nil {
+		return err
+	}
+
+	return s.file.Sync()
+}
+
+// Compact rewrites the log to hold only the stack's current values. The new log replaces the old
+// one atomically, so a crash during compaction leaves one or the other intact.
+func (s *Stack[T]) Compact() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.usable(); err != nil {
+		return err
+	}
+
+	return s.compact()
+}
+
+// Close flushes the log to stable storage and closes it. After the stack is closed, changes fail
+// with stack.ErrClosed, but its values can still be read. Closing a closed stack has no effect.
+func (s *Stack[T]) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	err := s.file.Sync()
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// usable returns an error if the stack can't record changes. The caller must hold the stack's
+// lock.
+func (s *Stack[T]) usable() error {
+	if s.closed {
+		return stack.ErrClosed
+	}
+
+	return s.broken
+}
+
+// setErr records err for Err if it is the first error. The caller must hold the stack's lock.
+func (s *Stack[T]) setErr(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// log appends a record to the log and flushes it according to the stack's sync policy. If the
+// record can't be written, the log is restored to its previous length. The caller must hold the
+// stack's lock.
+func (s *Stack[T]) log(op byte, payload []byte) error {
+	if err := s.usable(); err != nil {
+		return err
+	}
+
+	record := encode(op, payload)
+	if _, err := s.file.Write(record); err != nil {
+		// Whatever was written must go, or the next record would follow a damaged one.
+		if truncErr := s.rewind(); truncErr != nil {
+			s.broken = fmt.Errorf("durable: log unrecoverable after failed write: %w", truncErr)
+		}
+		return err
+	}
+
+	s.offset += int64(len(record))
+	s.records++
+
+	switch s.opts.Sync {
+	case SyncAlways:
+		if err := s.file.Sync(); err != nil {
+			// The record may or may not be durable, so the log no longer reliably matches memory.
+			s.broken = fmt.Errorf("durable: log unrecoverable after failed sync: %w", err)
+			return err
+		}
+
+	case SyncInterval:
+		if s.timer == nil {
+			s.timer = time.AfterFunc(s.opts.SyncInterval, s.backgroundSync)
+		}
+	}
+
+	return nil
+}
+
+// rewind truncates the log to its last valid record. The caller must hold the stack's lock.
+func (s *Stack[T]) rewind() error {
+	if err := s.file.Truncate(s.offset); err != nil {
+		return err
+	}
+
+	_, err := s.file.Seek(s.offset, io.SeekStart)
+	return err
+}
+
+// backgroundSync flushes the log for SyncInterval.
+func (s *Stack[T]) backgroundSync() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.timer = nil
+	if s.closed {
+		return
+	}
+
+	s.setErr(s.file.Sync())
+}
+
+// maybeCompact compacts the log if it holds too many obsolete records. The change that triggered
+// compaction has already been logged, so a failure here is recorded for Err rather than failing
+// the change. The caller must hold the stack's lock.
+func (s *Stack[T]) maybeCompact() {
+	if s.opts.CompactAfter < 0 || s.records-len(s.items) < s.opts.CompactAfter {
+		return
+	}
+
+	s.setErr(s.compact())
+}
+
+// compact rewrites the log to hold one push for each of the stack's values, in a new file that
+// then replaces the log. The caller must hold the stack's lock.
+func (s *Stack[T]) compact() error {
+	tmp := compactPath(s.path)
+	size, err := s.writeCompact(tmp)
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// From here on, the log on disk is the compacted one, whatever happens to the old file.
+	file, err := os.OpenFile(s.path, os.O_RDWR, 0)
+	if err == nil {
+		_, err = file.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		if file != nil {
+			file.Close()
+		}
+		s.broken = fmt.Errorf("durable: can't reopen compacted log: %w", err)
+		return err
+	}
+
+	s.file.Close()
+	s.file = file
+	s.offset = size
+	s.records = len(s.items)
+
+	// Make the rename itself durable. Not every platform supports syncing a directory, and the log
+	// is intact either way, so this is best effort.
+	if dir, err := os.Open(filepath.Dir(s.path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
+	return nil
+}
+
+// writeCompact writes a push record for each of the stack's values to a new file at path, flushes
+// it to stable storage, and returns its size. The caller must hold the stack's lock.
+func (s *Stack[T]) writeCompact(path string) (int64, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	var size int64
+	for _, v := range s.items {
+		payload, err := s.opts.Marshal(v)
+		if err != nil {
+			return 0, err
+		}
+
+		n, err := w.Write(encode(opPush, payload))
+		if err != nil {
+			return 0, err
+		}
+		size += int64(n)
+	}
+
+	if err := w.Flush(); err != nil {
+		return 0, err
+	}
+	if err := file.Sync(); err != nil {
+		return 0, err
+	}
+
+	return size, file.Close()
+}
+
+// encode returns a framed record for an operation and its payload.
+func encode(op byte, payload []byte) []byte {
+	record := make([]byte, headerSize+1+len(payload))
+	body := record[headerSize:]
+	body[0] = op
+	copy(body[1:], payload)
+
+	binary.LittleEndian.PutUint32(record[:4], uint32(len(body)))
+	binary.LittleEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(record[:4]))
+	binary.LittleEndian.PutUint32(record[8:12], crc32.ChecksumIEEE(body))
+
+	return record
+}
+
+// compactPath returns the path of the file a log at path is compacted into.
+func compactPath(path string) string {
+	return path + ".compact"
+}
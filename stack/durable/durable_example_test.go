@@ -0,0 +1,40 @@
+package durable_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/green-aloe/utilities/stack/durable"
+)
+
+func ExampleOpen() {
+	dir, err := os.MkdirTemp("", "example")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jobs.log")
+
+	s, err := durable.Open[string](path, durable.Options[string]{})
+	if err != nil {
+		panic(err)
+	}
+	s.Push("fetch")
+	s.Push("parse")
+	s.Push("index")
+	s.Pop()
+	s.Close()
+
+	// Opening the log again restores the stack.
+	s, err = durable.Open[string](path, durable.Options[string]{})
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	fmt.Println(s.Count(), s.Peek())
+
+	// Output:
+	// 2 parse
+}
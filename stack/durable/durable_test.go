@@ -0,0 +1,483 @@
+package durable
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/green-aloe/utilities/stack"
+	"github.com/stretchr/testify/require"
+)
+
+// open opens a stack logged at path and fails the test if it can't.
+func open[T any](t *testing.T, path string, opts Options[T]) *Stack[T] {
+	s, err := Open(path, opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// values returns a stack's values from bottom to top.
+func values[T any](s *Stack[T]) []T {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]T(nil), s.items...)
+}
+
+// Test_Open tests that Open restores a stack from its log.
+func Test_Open(t *testing.T) {
+	t.Run("new log", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[int](t, path, Options[int]{})
+		require.True(t, s.Empty())
+
+		_, err := os.Stat(path)
+		require.NoError(t, err)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := Open[int](filepath.Join(t.TempDir(), "missing", "log"), Options[int]{})
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("reopen", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[string](t, path, Options[string]{})
+		for _, v := range []string{"a", "b", "c", "d"} {
+			require.NoError(t, s.Push(v))
+		}
+		require.Equal(t, "d", s.Pop())
+		require.NoError(t, s.Clear())
+		for _, v := range []string{"e", "f", "g"} {
+			require.NoError(t, s.Push(v))
+		}
+		require.Equal(t, "g", s.Pop())
+		require.NoError(t, s.Close())
+
+		s = open[string](t, path, Options[string]{})
+		require.Equal(t, []string{"e", "f"}, values(s))
+
+		require.NoError(t, s.Push("h"))
+		require.NoError(t, s.Close())
+
+		s = open[string](t, path, Options[string]{})
+		require.Equal(t, []string{"e", "f", "h"}, values(s))
+	})
+
+	t.Run("truncated final record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[int](t, path, Options[int]{})
+		require.NoError(t, s.Push(1))
+		require.NoError(t, s.Push(2))
+		require.NoError(t, s.Close())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		full := len(data)
+		last := full - len(encode(opPush, []byte("2")))
+
+		// Every way of cutting the final record short drops just that record.
+		for size := last; size < full; size++ {
+			require.NoError(t, os.WriteFile(path, data[:size], 0o644))
+
+			s := open[int](t, path, Options[int]{})
+			require.Equal(t, []int{1}, values(s))
+			require.NoError(t, s.Push(3))
+			require.NoError(t, s.Close())
+
+			s = open[int](t, path, Options[int]{})
+			require.Equal(t, []int{1, 3}, values(s))
+			require.NoError(t, s.Close())
+		}
+	})
+
+	t.Run("torn final record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[int](t, path, Options[int]{})
+		require.NoError(t, s.Push(1))
+		require.NoError(t, s.Push(22))
+		require.NoError(t, s.Close())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		data[len(data)-1] = '3'
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+
+		s = open[int](t, path, Options[int]{})
+		require.Equal(t, []int{1}, values(s))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.EqualValues(t, len(encode(opPush, []byte("1"))), info.Size())
+	})
+
+	t.Run("corrupt record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[int](t, path, Options[int]{})
+		require.NoError(t, s.Push(1))
+		require.NoError(t, s.Push(2))
+		require.NoError(t, s.Close())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		data[headerSize+1] = '9'
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+
+		_, err = Open[int](path, Options[int]{})
+		require.ErrorIs(t, err, ErrCorrupt)
+	})
+
+	t.Run("corrupt length", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[int](t, path, Options[int]{})
+		for i := 1; i <= 5; i++ {
+			require.NoError(t, s.Push(i))
+		}
+		require.NoError(t, s.Close())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		second := len(encode(opPush, []byte("1")))
+		binary.LittleEndian.PutUint32(data[second:], 1<<30)
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+
+		_, err = Open[int](path, Options[int]{})
+		require.ErrorIs(t, err, ErrCorrupt)
+
+		// The log is left as it was.
+		have, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, data, have)
+	})
+
+	t.Run("partial final record", func(t *testing.T) {
+		record := encode(opPush, []byte("22"))
+		for name, n := range map[string]int{
+			"header": headerSize - 1,
+			"body":   headerSize + 1,
+		} {
+			t.Run(name, func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "log")
+				data := append(encode(opPush, []byte("1")), record[:n]...)
+				require.NoError(t, os.WriteFile(path, data, 0o644))
+
+				s := open[int](t, path, Options[int]{})
+				require.Equal(t, []int{1}, values(s))
+			})
+		}
+	})
+
+	t.Run("invalid record", func(t *testing.T) {
+		empty := make([]byte, headerSize)
+		binary.LittleEndian.PutUint32(empty[4:8], crc32.ChecksumIEEE(empty[:4]))
+		binary.LittleEndian.PutUint32(empty[8:], crc32.ChecksumIEEE(nil))
+
+		for name, record := range map[string][]byte{
+			"empty":   empty,
+			"pop":     encode(opPop, nil),
+			"unknown": encode(99, nil),
+			"value":   encode(opPush, []byte("x")),
+		} {
+			t.Run(name, func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "log")
+				require.NoError(t, os.WriteFile(path, append(record, encode(opClear, nil)...), 0o644))
+
+				_, err := Open[int](path, Options[int]{})
+				require.ErrorIs(t, err, ErrCorrupt)
+			})
+		}
+	})
+
+	t.Run("leftover compaction", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		require.NoError(t, os.WriteFile(compactPath(path), []byte("junk"), 0o644))
+
+		s := open[int](t, path, Options[int]{})
+		require.True(t, s.Empty())
+
+		_, err := os.Stat(compactPath(path))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("custom encoding", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		opts := Options[int]{
+			Marshal: func(v int) ([]byte, error) {
+				return []byte(strconv.Itoa(v * 10)), nil
+			},
+			Unmarshal: func(data []byte, v *int) error {
+				n, err := strconv.Atoi(string(data))
+				*v = n / 10
+				return err
+			},
+		}
+
+		s := open(t, path, opts)
+		require.NoError(t, s.Push(4))
+		require.NoError(t, s.Close())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "40", string(data[headerSize+1:]))
+
+		s = open(t, path, opts)
+		require.Equal(t, []int{4}, values(s))
+	})
+	t.Run("half-configured encoding", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+
+		_, err := Open(path, Options[int]{Marshal: func(int) ([]byte, error) { return nil, nil }})
+		require.Error(t, err)
+
+		_, err = Open(path, Options[int]{Unmarshal: func([]byte, *int) error { return nil }})
+		require.Error(t, err)
+
+		_, err = os.Stat(path)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+// Test_Stack_Push tests that Stack's Push method logs and adds values for various stack states.
+func Test_Stack_Push(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NoError(t, s.Push(1))
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		errMarshal := errors.New("marshal")
+		s := open(t, filepath.Join(t.TempDir(), "log"), Options[int]{
+			Marshal:   func(int) ([]byte, error) { return nil, errMarshal },
+			Unmarshal: func([]byte, *int) error { return nil },
+		})
+		require.ErrorIs(t, s.Push(1), errMarshal)
+		require.True(t, s.Empty())
+	})
+
+	t.Run("closed stack", func(t *testing.T) {
+		s := open[int](t, filepath.Join(t.TempDir(), "log"), Options[int]{})
+		require.NoError(t, s.Push(1))
+		require.NoError(t, s.Close())
+		require.NoError(t, s.Close())
+
+		require.ErrorIs(t, s.Push(2), stack.ErrClosed)
+		require.ErrorIs(t, s.Clear(), stack.ErrClosed)
+		require.ErrorIs(t, s.Sync(), stack.ErrClosed)
+		require.ErrorIs(t, s.Compact(), stack.ErrClosed)
+
+		_, ok := s.CheckPop()
+		require.False(t, ok)
+		require.NoError(t, s.Err())
+
+		require.Equal(t, 1, s.Peek())
+		require.Equal(t, 1, s.Count())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[int](t, path, Options[int]{Sync: SyncNever, CompactAfter: 64})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					require.NoError(t, s.Push(i*10+j))
+					if j%2 == 1 {
+						s.Pop()
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+		require.NoError(t, s.Err())
+
+		want := values(s)
+		require.Len(t, want, 500)
+		require.NoError(t, s.Close())
+
+		s = open[int](t, path, Options[int]{})
+		require.Equal(t, want, values(s))
+
+		sort.Ints(want)
+		for i := 1; i < len(want); i++ {
+			require.NotEqual(t, want[i-1], want[i])
+		}
+	})
+}
+
+// Test_Stack_Pop tests that Stack's Pop and CheckPop methods log and remove values for various
+// stack states.
+func Test_Stack_Pop(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.Zero(t, s.Pop())
+
+		v, ok := s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, v)
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[int](t, path, Options[int]{})
+
+		v, ok := s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, v)
+		require.NoError(t, s.Err())
+
+		// Nothing is logged for a pop that doesn't remove anything.
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Zero(t, info.Size())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		s := open[int](t, filepath.Join(t.TempDir(), "log"), Options[int]{})
+		require.NoError(t, s.Push(1))
+		require.NoError(t, s.Push(2))
+
+		require.Equal(t, 2, s.Peek())
+		v, ok := s.CheckPop()
+		require.True(t, ok)
+		require.Equal(t, 2, v)
+		require.Equal(t, 1, s.Pop())
+		require.True(t, s.Empty())
+	})
+}
+
+// Test_Stack_Compact tests that a stack's log is compacted, automatically and on demand, without
+// changing the stack's values.
+func Test_Stack_Compact(t *testing.T) {
+	size := func(t *testing.T, path string) int64 {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		return info.Size()
+	}
+
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NoError(t, s.Compact())
+	})
+
+	t.Run("on demand", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[int](t, path, Options[int]{CompactAfter: -1})
+		for i := 0; i < 100; i++ {
+			require.NoError(t, s.Push(i))
+			require.NoError(t, s.Push(i))
+			s.Pop()
+		}
+		require.Equal(t, 300, s.records)
+
+		require.NoError(t, s.Compact())
+		require.Equal(t, 100, s.records)
+		require.Equal(t, s.offset, size(t, path))
+
+		// The compacted log is still appended to.
+		require.NoError(t, s.Push(100))
+		require.NoError(t, s.Close())
+
+		s = open[int](t, path, Options[int]{})
+		want := make([]int, 101)
+		for i := range want {
+			want[i] = i
+		}
+		require.Equal(t, want, values(s))
+	})
+
+	t.Run("automatic", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[int](t, path, Options[int]{CompactAfter: 10})
+		for i := 0; i < 1000; i++ {
+			require.NoError(t, s.Push(i))
+			s.Pop()
+			require.Less(t, s.records, 11)
+		}
+		require.NoError(t, s.Push(1))
+		require.NoError(t, s.Err())
+		require.LessOrEqual(t, size(t, path), int64(11*len(encode(opPush, []byte("999")))))
+		require.NoError(t, s.Close())
+
+		_, err := os.Stat(compactPath(path))
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		s = open[int](t, path, Options[int]{})
+		require.Equal(t, []int{1}, values(s))
+	})
+
+	t.Run("on open", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[int](t, path, Options[int]{CompactAfter: -1})
+		for i := 0; i < 10; i++ {
+			require.NoError(t, s.Push(i))
+			require.NoError(t, s.Clear())
+		}
+		require.NoError(t, s.Close())
+
+		s = open[int](t, path, Options[int]{CompactAfter: 5})
+		require.Zero(t, s.records)
+		require.Zero(t, size(t, path))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log")
+		s := open[int](t, path, Options[int]{CompactAfter: -1})
+		require.NoError(t, s.Push(1))
+
+		// A directory where the compacted log should go makes compaction fail.
+		require.NoError(t, os.MkdirAll(filepath.Join(compactPath(path), "dir"), 0o755))
+		require.Error(t, s.Compact())
+		require.NoError(t, os.RemoveAll(compactPath(path)))
+
+		require.NoError(t, s.Push(2))
+		require.NoError(t, s.Close())
+
+		s = open[int](t, path, Options[int]{})
+		require.Equal(t, []int{1, 2}, values(s))
+	})
+}
+
+// Test_Stack_Sync tests that each sync policy flushes the log.
+func Test_Stack_Sync(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NoError(t, s.Sync())
+		require.NoError(t, s.Close())
+		require.NoError(t, s.Err())
+	})
+
+	for _, policy := range []SyncPolicy{SyncAlways, SyncInterval, SyncNever} {
+		t.Run(strconv.Itoa(int(policy)), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "log")
+			s := open[int](t, path, Options[int]{Sync: policy, SyncInterval: time.Millisecond})
+			require.NoError(t, s.Push(1))
+			require.NoError(t, s.Sync())
+
+			if policy == SyncInterval {
+				require.Eventually(t, func() bool {
+					s.mutex.Lock()
+					defer s.mutex.Unlock()
+					return s.timer == nil
+				}, time.Second, time.Millisecond)
+			}
+
+			require.NoError(t, s.Push(2))
+			require.NoError(t, s.Close())
+			require.NoError(t, s.Err())
+
+			s = open[int](t, path, Options[int]{})
+			require.Equal(t, []int{1, 2}, values(s))
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package stack
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+const (
+	// eliminationSlots is the number of slots in an elimination stack's exchange array.
+	eliminationSlots = 8
+	// eliminationSpins is how many times a push waits for a pop to take its offer before giving
+	// up and going back to the shared stack.
+	eliminationSpins = 32
+)
+
+const (
+	offerWaiting int32 = iota
+	offerTaken
+	offerWithdrawn
+)
+
+// An Elimination stack is a lock-free first-in-last-out (FILO) data structure for workloads with
+// extreme contention. It behaves like LockFree, but when a push or pop loses a race for the top of
+// the stack, it backs off to an exchange array where a concurrent push and pop can cancel each
+// other out: the pop takes the push's value directly, and neither touches the shared top. The zero
+// value is an empty stack and ready to use.
+//
+// Values exchanged this way never appear on the stack, which is exactly what would have happened
+// if the push had landed and been popped immediately after, so every operation is still
+// linearizable.
+type Elimination[T any] struct {
+	top   atomic.Pointer[node[T]]
+	slots [eliminationSlots]atomic.Pointer[offer[T]]
+}
+
+// An offer is a value that a push is waiting to hand directly to a pop.
+type offer[T any] struct {
+	value T
+	state atomic.Int32
+}
+
+// Push adds a value to the top of the stack.
+func (s *Elimination[T]) Push(v T) {
+	if s == nil {
+		return
+	}
+
+	n := &node[T]{value: v}
+	for {
+		top := s.top.Load()
+		n.next = top
+		n.depth = top.count() + 1
+
+		if s.top.CompareAndSwap(top, n) {
+			return
+		}
+
+		if s.offer(v) {
+			return
+		}
+	}
+}
+
+// Pop removes and returns the value at the top of the stack. If the stack is empty, this returns
+// the zero value of the stack's type.
+func (s *Elimination[T]) Pop() (t T) {
+	t, _ = s.CheckPop()
+	return t
+}
+
+// CheckPop returns the value at the top of the stack and a boolean indicating whether the stack is
+// empty. If the stack is empty, this returns the zero value of the stack's type and false.
+func (s *Elimination[T]) CheckPop() (t T, ok bool) {
+	if s == nil {
+		return
+	}
+
+	for {
+		top := s.top.Load()
+		if top == nil {
+			return
+		}
+
+		if s.top.CompareAndSwap(top, top.next) {
+			return top.value, true
+		}
+
+		if t, ok := s.take(); ok {
+			return t, true
+		}
+	}
+}
+
+// Peek returns the value at the top of the stack without removing it. If the stack is empty, this
+// returns the zero value of the stack's type.
+func (s *Elimination[T]) Peek() (t T) {
+	if s == nil {
+		return
+	}
+
+	if top := s.top.Load(); top != nil {
+		return top.value
+	}
+
+	return
+}
+
+// Empty returns true if the stack is empty.
+func (s *Elimination[T]) Empty() bool {
+	if s == nil {
+		return true
+	}
+
+	return s.top.Load() == nil
+}
+
+// Count returns the number of elements in the stack.
+func (s *Elimination[T]) Count() int {
+	if s == nil {
+		return 0
+	}
+
+	return s.top.Load().count()
+}
+
+// Clear removes all elements from the stack.
+func (s *Elimination[T]) Clear() {
+	if s == nil {
+		return
+	}
+
+	s.top.Store(nil)
+}
+
+// offer posts v in a random exchange slot and waits briefly for a pop to take it. It reports
+// whether a pop took the value, in which case the push is complete.
+func (s *Elimination[T]) offer(v T) bool {
+	slot := &s.slots[rand.IntN(eliminationSlots)]
+
+	o := &offer[T]{value: v}
+	if !slot.CompareAndSwap(nil, o) {
+		// Another push is already waiting in this slot.
+		return false
+	}
+
+	for i := 0; i < eliminationSpins; i++ {
+		if o.state.Load() == offerTaken {
+			return true
+		}
+		runtime.Gosched()
+	}
+
+	if o.state.CompareAndSwap(offerWaiting, offerWithdrawn) {
+		slot.CompareAndSwap(o, nil)
+		return false
+	}
+
+	// A pop took the value after we stopped waiting but before we could withdraw it.
+	return true
+}
+
+// take looks for a waiting push in a random exchange slot and, if it finds one, takes its value.
+// It reports whether a value was taken.
+func (s *Elimination[T]) take() (t T, ok bool) {
+	slot := &s.slots[rand.IntN(eliminationSlots)]
+
+	o := slot.Load()
+	if o == nil {
+		return
+	}
+
+	if !o.state.CompareAndSwap(offerWaiting, offerTaken) {
+		return
+	}
+	slot.CompareAndSwap(o, nil)
+
+	return o.value, true
+}
@@ -0,0 +1,23 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleElimination() {
+	var s stack.Elimination[int]
+	for i := 1; i <= 3; i++ {
+		s.Push(i)
+	}
+
+	fmt.Println(s.Count(), s.Peek())
+	fmt.Println(s.Pop(), s.Pop(), s.Pop())
+	fmt.Println(s.Empty())
+
+	// Output:
+	// 3 3
+	// 3 2 1
+	// true
+}
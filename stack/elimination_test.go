@@ -0,0 +1,387 @@
+package stack
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Elimination_Push tests that Elimination's Push method adds a value to the top of the stack
+// for various stack configurations.
+func Test_Elimination_Push(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Elimination[int32]
+		require.NotPanics(t, func() { s.Push(1) })
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Elimination[string]
+		s.Push("1")
+		s.Push("2")
+		s.Push("3")
+		require.Equal(t, 3, s.Count())
+		require.Equal(t, "3", s.Peek())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Elimination[int]
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.Push(i)
+			}(i)
+		}
+
+		wg.Wait()
+		require.Equal(t, 100, s.Count())
+	})
+}
+
+// Test_Elimination_CheckPop tests that Elimination's Pop and CheckPop methods remove and return the
+// value at the top of the stack for various stack configurations.
+func Test_Elimination_CheckPop(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Elimination[uint8]
+		require.Zero(t, s.Pop())
+
+		top, ok := s.CheckPop()
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Elimination[string]
+		require.Zero(t, s.Pop())
+
+		top, ok := s.CheckPop()
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Elimination[int]
+		for i := 1; i <= 10; i++ {
+			s.Push(i)
+		}
+		for i := 10; i >= 6; i-- {
+			top, ok := s.CheckPop()
+			require.Equal(t, i, top)
+			require.True(t, ok)
+		}
+		for i := 5; i >= 1; i-- {
+			require.Equal(t, i, s.Pop())
+		}
+
+		top, ok := s.CheckPop()
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Elimination[int]
+
+		var want []int
+		for i := 0; i < 100; i++ {
+			s.Push(i)
+			want = append(want, i)
+		}
+
+		ch := make(chan int, 100)
+		for i := 0; i < 100; i++ {
+			go func() {
+				top, ok := s.CheckPop()
+				require.True(t, ok)
+				ch <- top
+			}()
+		}
+
+		var have []int
+		for i := 0; i < 100; i++ {
+			have = append(have, <-ch)
+		}
+		sort.Ints(have)
+		require.Equal(t, want, have)
+		require.True(t, s.Empty())
+	})
+}
+
+// Test_Elimination_Peek tests that Elimination's Peek, Empty, Count, and Clear methods report and
+// reset the stack's state for various stack configurations.
+func Test_Elimination_Peek(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Elimination[[]rune]
+		require.Zero(t, s.Peek())
+		require.True(t, s.Empty())
+		require.Zero(t, s.Count())
+		require.NotPanics(t, func() { s.Clear() })
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Elimination[[]rune]
+		require.Zero(t, s.Peek())
+		require.True(t, s.Empty())
+		require.Zero(t, s.Count())
+		require.NotPanics(t, func() { s.Clear() })
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Elimination[rune]
+		s.Push('a')
+		s.Push('b')
+		require.Equal(t, 'b', s.Peek())
+		require.False(t, s.Empty())
+		require.Equal(t, 2, s.Count())
+
+		s.Clear()
+		require.Zero(t, s.Peek())
+		require.True(t, s.Empty())
+		require.Zero(t, s.Count())
+	})
+}
+
+// Test_Elimination_exchange tests that a push and a pop that meet in the exchange array hand a value
+// directly from one to the other without touching the stack.
+func Test_Elimination_exchange(t *testing.T) {
+	var s Elimination[int]
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for !s.offer(42) {
+		}
+	}()
+
+	var have int
+	for {
+		if v, ok := s.take(); ok {
+			have = v
+			break
+		}
+	}
+	<-done
+
+	require.Equal(t, 42, have)
+	require.True(t, s.Empty())
+	for i := range s.slots {
+		require.Nil(t, s.slots[i].Load())
+	}
+}
+
+// Test_Elimination_ConcurrentUse tests that every value pushed onto an Elimination stack by many
+// goroutines is popped exactly once.
+func Test_Elimination_ConcurrentUse(t *testing.T) {
+	var s Elimination[int]
+
+	const goroutines, perGoroutine = 16, 10_000
+
+	var wg sync.WaitGroup
+	results := make([][]int, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				s.Push(g*perGoroutine + i)
+				if v, ok := s.CheckPop(); ok {
+					results[g] = append(results[g], v)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for v, ok := s.CheckPop(); ok; v, ok = s.CheckPop() {
+		results[0] = append(results[0], v)
+	}
+
+	seen := make([]bool, goroutines*perGoroutine)
+	for _, r := range results {
+		for _, v := range r {
+			require.False(t, seen[v], "value %d popped twice", v)
+			seen[v] = true
+		}
+	}
+	for v, ok := range seen {
+		require.True(t, ok, "value %d never popped", v)
+	}
+}
+
+// Test_Linearizability tests that concurrent histories of pushes and pops on the lock-free stacks
+// can always be explained by some sequential order of the same operations that respects real time.
+func Test_Linearizability(t *testing.T) {
+	type stacker interface {
+		Push(int)
+		CheckPop() (int, bool)
+	}
+
+	for name, newStack := range map[string]func() stacker{
+		"LockFree":    func() stacker { return &LockFree[int]{} },
+		"Elimination": func() stacker { return &Elimination[int]{} },
+	} {
+		t.Run(name, func(t *testing.T) {
+			const rounds, goroutines, opsPerGoroutine = 2_000, 4, 4
+
+			for round := 0; round < rounds; round++ {
+				s := newStack()
+
+				var clock atomic.Int64
+				var nextValue atomic.Int64
+				histories := make([][]historyOp, goroutines)
+
+				var wg sync.WaitGroup
+				for g := 0; g < goroutines; g++ {
+					wg.Add(1)
+					go func(g int) {
+						defer wg.Done()
+						for i := 0; i < opsPerGoroutine; i++ {
+							var op historyOp
+							if rand.IntN(2) == 0 {
+								op.push = true
+								op.value = int(nextValue.Add(1))
+								op.call = clock.Add(1)
+								s.Push(op.value)
+							} else {
+								op.call = clock.Add(1)
+								op.value, op.ok = s.CheckPop()
+							}
+							op.ret = clock.Add(1)
+							histories[g] = append(histories[g], op)
+						}
+					}(g)
+				}
+				wg.Wait()
+
+				var history []historyOp
+				for _, h := range histories {
+					history = append(history, h...)
+				}
+				require.True(t, linearizable(history), "history is not linearizable: %+v", history)
+			}
+		})
+	}
+}
+
+// Test_linearizable tests that the linearizability checker rejects histories that no sequential
+// stack could produce.
+func Test_linearizable(t *testing.T) {
+	t.Run("sequential", func(t *testing.T) {
+		require.True(t, linearizable([]historyOp{
+			{push: true, value: 1, call: 1, ret: 2},
+			{push: true, value: 2, call: 3, ret: 4},
+			{value: 2, ok: true, call: 5, ret: 6},
+			{value: 1, ok: true, call: 7, ret: 8},
+			{call: 9, ret: 10},
+		}))
+	})
+
+	t.Run("overlapping", func(t *testing.T) {
+		require.True(t, linearizable([]historyOp{
+			{push: true, value: 1, call: 1, ret: 4},
+			{value: 1, ok: true, call: 2, ret: 3},
+		}))
+	})
+
+	t.Run("wrong order", func(t *testing.T) {
+		require.False(t, linearizable([]historyOp{
+			{push: true, value: 1, call: 1, ret: 2},
+			{push: true, value: 2, call: 3, ret: 4},
+			{value: 1, ok: true, call: 5, ret: 6},
+		}))
+	})
+
+	t.Run("pop before push", func(t *testing.T) {
+		require.False(t, linearizable([]historyOp{
+			{value: 1, ok: true, call: 1, ret: 2},
+			{push: true, value: 1, call: 3, ret: 4},
+		}))
+	})
+
+	t.Run("empty pop on non-empty stack", func(t *testing.T) {
+		require.False(t, linearizable([]historyOp{
+			{push: true, value: 1, call: 1, ret: 2},
+			{call: 3, ret: 4},
+		}))
+	})
+}
+
+// A historyOp is one completed push or pop in a concurrent history. call and ret are logical
+// timestamps taken immediately before and after the operation.
+type historyOp struct {
+	push  bool
+	value int
+	ok    bool
+	call  int64
+	ret   int64
+}
+
+// linearizable reports whether the operations in history can be put in a sequential order that is
+// valid for a stack and in which every operation that returned before another was called comes
+// first. It uses the Wing & Gong backtracking search, which is only practical for short histories.
+func linearizable(history []historyOp) bool {
+	done := make([]bool, len(history))
+	var model []int
+
+	var search func(remaining int) bool
+	search = func(remaining int) bool {
+		if remaining == 0 {
+			return true
+		}
+
+		// Only operations that were called before every other remaining operation returned can
+		// take effect next.
+		minRet := int64(math.MaxInt64)
+		for i, op := range history {
+			if !done[i] {
+				minRet = min(minRet, op.ret)
+			}
+		}
+
+		for i, op := range history {
+			if done[i] || op.call > minRet {
+				continue
+			}
+
+			switch {
+			case op.push:
+				model = append(model, op.value)
+				done[i] = true
+				if search(remaining - 1) {
+					return true
+				}
+				done[i] = false
+				model = model[:len(model)-1]
+
+			case !op.ok:
+				if len(model) == 0 {
+					done[i] = true
+					if search(remaining - 1) {
+						return true
+					}
+					done[i] = false
+				}
+
+			case len(model) > 0 && model[len(model)-1] == op.value:
+				model = model[:len(model)-1]
+				done[i] = true
+				if search(remaining - 1) {
+					return true
+				}
+				done[i] = false
+				model = append(model, op.value)
+			}
+		}
+
+		return false
+	}
+
+	return search(len(history))
+}
@@ -0,0 +1,143 @@
+package stack
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+)
+
+// MarshalJSON implements json.Marshaler. The stack is encoded as a JSON array of its values from
+// bottom to top, so the last element of the array is the top of the stack. The stack is locked
+// while it is encoded, so the values' own MarshalJSON methods must not use the stack.
+func (s *Stack[T]) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.items == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal(s.items)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces the stack's contents with the values in a
+// JSON array, ordered from bottom to top as MarshalJSON encodes them. Any goroutines waiting in
+// PopWait are then handed values from the top of the new contents. See Stack.Do for the ways that
+// replacing the contents can fail.
+func (s *Stack[T]) UnmarshalJSON(data []byte) error {
+	if s == nil {
+		return errors.New("stack: UnmarshalJSON on nil pointer")
+	}
+
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	return s.replace(items)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The stack's values are encoded from bottom to
+// top with encoding/gob, so the same restrictions on types apply. The stack is locked while it is
+// encoded.
+func (s *Stack[T]) MarshalBinary() ([]byte, error) {
+	var items []T
+	if s != nil {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		items = s.items
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(items); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It replaces the stack's contents with the
+// values encoded by MarshalBinary. Any goroutines waiting in PopWait are then handed values from the
+// top of the new contents. See Stack.Do for the ways that replacing the contents can fail.
+func (s *Stack[T]) UnmarshalBinary(data []byte) error {
+	if s == nil {
+		return errors.New("stack: UnmarshalBinary on nil pointer")
+	}
+
+	var items []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&items); err != nil {
+		return err
+	}
+
+	return s.replace(items)
+}
+
+// GobEncode implements gob.GobEncoder. It is equivalent to MarshalBinary.
+func (s *Stack[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder. It is equivalent to UnmarshalBinary.
+func (s *Stack[T]) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+// replace atomically replaces the stack's contents with items, ordered from bottom to top. The
+// new contents are installed as a whole before any goroutines waiting in PopWait are served, so the
+// waiters receive the values at the top, as if they had popped them.
+func (s *Stack[T]) replace(items []T) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(items) > 0 {
+		if s.closed {
+			return ErrClosed
+		}
+
+		// Values handed to waiters don't take up any room.
+		stored := len(items) - min(len(s.waiters), len(items))
+		if s.capacity > 0 && stored > s.capacity && s.policy != OverflowEvict {
+			return ErrFull
+		}
+	}
+
+	if len(s.items) > 0 {
+		s.notifyPopped(s.items)
+		s.stats.popped(len(s.items))
+		clear(s.items)
+		s.items = s.items[:0]
+		s.removed()
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	s.items = append(s.items, items...)
+	s.notify(EventPushed, items...)
+	s.stats.pushed(len(items), s.depth())
+
+	for len(s.waiters) > 0 {
+		v, ok := s.pop()
+		if !ok {
+			break
+		}
+
+		ch := s.waiters[0]
+		s.waiters[0] = nil
+		s.waiters = s.waiters[1:]
+
+		// The channel is buffered and receives at most one value, so this never blocks.
+		ch <- v
+	}
+
+	s.evict()
+
+	return nil
+}
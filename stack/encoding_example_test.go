@@ -0,0 +1,31 @@
+package stack_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleStack_MarshalJSON() {
+	var s stack.Stack[string]
+	s.PushMany("open", "edit", "save")
+
+	data, _ := json.Marshal(&s)
+	fmt.Println(string(data))
+
+	// Output:
+	// ["open","edit","save"]
+}
+
+func ExampleStack_UnmarshalJSON() {
+	var s stack.Stack[string]
+	json.Unmarshal([]byte(`["open","edit","save"]`), &s)
+
+	fmt.Println(s.Pop())
+	fmt.Println(s.Count())
+
+	// Output:
+	// save
+	// 2
+}
@@ -0,0 +1,241 @@
+package stack
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Stack_JSON tests that Stack's MarshalJSON and UnmarshalJSON methods round-trip the stack's
+// values in order for various stack configurations.
+func Test_Stack_JSON(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		data, err := s.MarshalJSON()
+		require.NoError(t, err)
+		require.JSONEq(t, "[]", string(data))
+
+		require.Error(t, s.UnmarshalJSON([]byte("[1]")))
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		data, err := json.Marshal(&s)
+		require.NoError(t, err)
+		require.JSONEq(t, "[]", string(data))
+
+		var s2 Stack[int]
+		require.NoError(t, json.Unmarshal(data, &s2))
+		require.True(t, s2.Empty())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b", "c")
+
+		data, err := json.Marshal(&s)
+		require.NoError(t, err)
+		require.JSONEq(t, `["a","b","c"]`, string(data))
+
+		var s2 Stack[string]
+		s2.Push("z")
+		require.NoError(t, json.Unmarshal(data, &s2))
+		require.Equal(t, []string{"c", "b", "a"}, s2.Drain())
+		require.Equal(t, 3, s.Count())
+	})
+
+	t.Run("struct field", func(t *testing.T) {
+		type history struct {
+			Name  string
+			Edits Stack[int]
+		}
+
+		var h history
+		h.Name = "doc"
+		h.Edits.PushMany(1, 2)
+
+		data, err := json.Marshal(&h)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"Name":"doc","Edits":[1,2]}`, string(data))
+
+		var h2 history
+		require.NoError(t, json.Unmarshal(data, &h2))
+		require.Equal(t, "doc", h2.Name)
+		require.Equal(t, []int{2, 1}, h2.Edits.Drain())
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		require.Error(t, json.Unmarshal([]byte(`["a"]`), &s))
+		require.Equal(t, []int{1}, s.Drain())
+	})
+
+	t.Run("bounded stack", func(t *testing.T) {
+		s := NewBounded[int](2, OverflowReject)
+		require.ErrorIs(t, json.Unmarshal([]byte(`[1,2,3]`), s), ErrFull)
+		require.True(t, s.Empty())
+
+		s = NewBounded[int](2, OverflowEvict)
+		require.NoError(t, json.Unmarshal([]byte(`[1,2,3]`), s))
+		require.Equal(t, []int{3, 2}, s.Drain())
+	})
+
+	t.Run("closed stack", func(t *testing.T) {
+		var s Stack[int]
+		s.Close()
+		require.ErrorIs(t, json.Unmarshal([]byte(`[1]`), &s), ErrClosed)
+	})
+
+	t.Run("waiters", func(t *testing.T) {
+		var s Stack[int]
+
+		ch := make(chan int)
+		go func() {
+			top, err := s.PopWait(context.Background())
+			require.NoError(t, err)
+			ch <- top
+		}()
+		awaitWaiters(t, &s, 1)
+
+		require.NoError(t, json.Unmarshal([]byte(`[1,2,3]`), &s))
+		require.Equal(t, 3, <-ch)
+		require.Equal(t, []int{2, 1}, s.Drain())
+	})
+
+	t.Run("bounded stack, waiters", func(t *testing.T) {
+		s := NewBounded[int](2, OverflowReject)
+
+		ch := make(chan int)
+		go func() {
+			top, err := s.PopWait(context.Background())
+			require.NoError(t, err)
+			ch <- top
+		}()
+		awaitWaiters(t, s, 1)
+
+		require.NoError(t, json.Unmarshal([]byte(`[1,2,3]`), s))
+		require.Equal(t, 3, <-ch)
+		require.Equal(t, []int{2, 1}, s.Drain())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				s.Push(i)
+			}(i)
+			go func() {
+				defer wg.Done()
+				_, err := json.Marshal(&s)
+				require.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		data, err := json.Marshal(&s)
+		require.NoError(t, err)
+
+		var have []int
+		require.NoError(t, json.Unmarshal(data, &have))
+		require.Len(t, have, 100)
+	})
+}
+
+// Test_Stack_Binary tests that Stack's MarshalBinary and UnmarshalBinary methods round-trip the
+// stack's values in order for various stack configurations.
+func Test_Stack_Binary(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		data, err := s.MarshalBinary()
+		require.NoError(t, err)
+
+		var s2 Stack[int]
+		require.NoError(t, s2.UnmarshalBinary(data))
+		require.True(t, s2.Empty())
+
+		require.Error(t, s.UnmarshalBinary(data))
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[float64]
+		s.PushMany(1.5, 2.5, 3.5)
+
+		data, err := s.MarshalBinary()
+		require.NoError(t, err)
+
+		var s2 Stack[float64]
+		require.NoError(t, s2.UnmarshalBinary(data))
+		require.Equal(t, []float64{3.5, 2.5, 1.5}, s2.Drain())
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		require.Error(t, s.UnmarshalBinary([]byte("not gob")))
+		require.Equal(t, 1, s.Count())
+	})
+
+	t.Run("waiters", func(t *testing.T) {
+		var src Stack[int]
+		src.PushMany(1, 2, 3)
+		data, err := src.MarshalBinary()
+		require.NoError(t, err)
+
+		var s Stack[int]
+		ch := make(chan int)
+		go func() {
+			top, err := s.PopWait(context.Background())
+			require.NoError(t, err)
+			ch <- top
+		}()
+		awaitWaiters(t, &s, 1)
+
+		require.NoError(t, s.UnmarshalBinary(data))
+		require.Equal(t, 3, <-ch)
+		require.Equal(t, []int{2, 1}, s.Drain())
+	})
+}
+
+// Test_Stack_Gob tests that a Stack can be encoded and decoded with encoding/gob, on its own and as
+// part of another value.
+func Test_Stack_Gob(t *testing.T) {
+	t.Run("stack", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b", "c")
+
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(&s))
+
+		var s2 Stack[string]
+		require.NoError(t, gob.NewDecoder(&buf).Decode(&s2))
+		require.Equal(t, []string{"c", "b", "a"}, s2.Drain())
+	})
+
+	t.Run("struct field", func(t *testing.T) {
+		type history struct {
+			Name  string
+			Edits *Stack[int]
+		}
+
+		h := history{Name: "doc", Edits: &Stack[int]{}}
+		h.Edits.PushMany(1, 2, 3)
+
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(h))
+
+		var h2 history
+		require.NoError(t, gob.NewDecoder(&buf).Decode(&h2))
+		require.Equal(t, "doc", h2.Name)
+		require.Equal(t, []int{3, 2, 1}, h2.Edits.Drain())
+	})
+}
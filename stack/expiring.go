@@ -0,0 +1,218 @@
+package stack
+
+import (
+	"sync"
+	"time"
+)
+
+// An Expiring stack is a first-in-last-out (FILO) data structure whose values can each have a
+// deadline, after which they expire. Expired values are skipped by Pop, CheckPop, and Peek and
+// aren't counted by Count, as if they had been removed the moment they expired. The zero value is
+// an empty stack that uses the system clock and is ready to use. An Expiring stack is safe for
+// concurrent use.
+//
+// Expired values are actually removed lazily, the next time the stack is used after the earliest
+// deadline on it passes. Removing them takes time proportional to the size of the stack.
+type Expiring[T any] struct {
+	// Now returns the current time. If it is nil, the stack uses time.Now.
+	Now func() time.Time
+	// OnExpire is called with each value that the stack removes because it expired, along with its
+	// deadline. Values are reported from bottom to top. OnExpire is called without the stack locked,
+	// so it can use the stack, but by the time it runs other goroutines may have changed the stack.
+	OnExpire func(v T, deadline time.Time)
+
+	entries []expiringEntry[T]
+
+	// earliest is the earliest deadline of any value on the stack, or the zero time if none of
+	// them expire.
+	earliest time.Time
+
+	mutex sync.Mutex
+}
+
+// An expiringEntry is a value on an Expiring stack, along with its deadline.
+type expiringEntry[T any] struct {
+	value    T
+	deadline time.Time
+}
+
+// Push adds a value to the top of the stack that expires at deadline. If deadline is the zero
+// time, the value never expires. If deadline has already passed, the value expires immediately.
+func (s *Expiring[T]) Push(v T, deadline time.Time) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	s.entries = append(s.entries, expiringEntry[T]{value: v, deadline: deadline})
+	if !deadline.IsZero() && (s.earliest.IsZero() || deadline.Before(s.earliest)) {
+		s.earliest = deadline
+	}
+	expired := s.purge()
+	s.mutex.Unlock()
+
+	s.expire(expired)
+}
+
+// PushFor adds a value to the top of the stack that expires after ttl has elapsed, according to
+// the stack's clock. If ttl is not positive, the value expires immediately.
+func (s *Expiring[T]) PushFor(v T, ttl time.Duration) {
+	if s == nil {
+		return
+	}
+
+	s.Push(v, s.now().Add(ttl))
+}
+
+// Pop removes and returns the value at the top of the stack that hasn't expired. If the stack has
+// no such value, this returns the zero value of the stack's type.
+func (s *Expiring[T]) Pop() (t T) {
+	t, _ = s.CheckPop()
+	return t
+}
+
+// CheckPop returns the value at the top of the stack that hasn't expired and a boolean indicating
+// whether the stack is empty. If the stack has no such value, this returns the zero value of the
+// stack's type and false.
+func (s *Expiring[T]) CheckPop() (t T, ok bool) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	expired := s.purge()
+	if n := len(s.entries); n > 0 {
+		t, ok = s.entries[n-1].value, true
+		s.entries[n-1] = expiringEntry[T]{}
+		s.entries = s.entries[:n-1]
+	}
+	s.mutex.Unlock()
+
+	s.expire(expired)
+
+	return t, ok
+}
+
+// Peek returns the value at the top of the stack that hasn't expired without removing it. If the
+// stack has no such value, this returns the zero value of the stack's type.
+func (s *Expiring[T]) Peek() (t T) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	expired := s.purge()
+	if n := len(s.entries); n > 0 {
+		t = s.entries[n-1].value
+	}
+	s.mutex.Unlock()
+
+	s.expire(expired)
+
+	return t
+}
+
+// Empty returns true if the stack has no values that haven't expired.
+func (s *Expiring[T]) Empty() bool {
+	return s.Count() == 0
+}
+
+// Count returns the number of values on the stack that haven't expired.
+func (s *Expiring[T]) Count() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.Lock()
+	expired := s.purge()
+	n := len(s.entries)
+	s.mutex.Unlock()
+
+	s.expire(expired)
+
+	return n
+}
+
+// Purge removes every expired value from the stack now, instead of waiting for the stack to be
+// used, and returns the number of values removed.
+func (s *Expiring[T]) Purge() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.Lock()
+	expired := s.purge()
+	s.mutex.Unlock()
+
+	s.expire(expired)
+
+	return len(expired)
+}
+
+// Clear removes all values from the stack. Values removed this way aren't reported to OnExpire,
+// even if they had expired.
+func (s *Expiring[T]) Clear() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries = nil
+	s.earliest = time.Time{}
+}
+
+// purge removes every expired value from the stack and returns them, from bottom to top. The
+// caller must hold the stack's lock, and must pass the result to expire after releasing it.
+func (s *Expiring[T]) purge() []expiringEntry[T] {
+	if s.earliest.IsZero() {
+		return nil
+	}
+
+	now := s.now()
+	if now.Before(s.earliest) {
+		return nil
+	}
+
+	var expired []expiringEntry[T]
+	kept := 0
+	s.earliest = time.Time{}
+	for _, e := range s.entries {
+		if !e.deadline.IsZero() && !now.Before(e.deadline) {
+			expired = append(expired, e)
+			continue
+		}
+
+		if !e.deadline.IsZero() && (s.earliest.IsZero() || e.deadline.Before(s.earliest)) {
+			s.earliest = e.deadline
+		}
+		s.entries[kept] = e
+		kept++
+	}
+	clear(s.entries[kept:])
+	s.entries = s.entries[:kept]
+
+	return expired
+}
+
+// expire reports expired values to the stack's OnExpire callback. The caller must not hold the
+// stack's lock.
+func (s *Expiring[T]) expire(expired []expiringEntry[T]) {
+	if s.OnExpire == nil {
+		return
+	}
+
+	for _, e := range expired {
+		s.OnExpire(e.value, e.deadline)
+	}
+}
+
+// now returns the current time according to the stack's clock.
+func (s *Expiring[T]) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+
+	return s.Now()
+}
@@ -0,0 +1,30 @@
+package stack_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleExpiring() {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := stack.Expiring[string]{
+		Now: func() time.Time { return now },
+		OnExpire: func(v string, _ time.Time) {
+			fmt.Println("expired:", v)
+		},
+	}
+
+	s.Push("retry later", time.Time{})
+	s.PushFor("retry soon", time.Minute)
+	fmt.Println(s.Count(), s.Peek())
+
+	now = now.Add(time.Minute)
+	fmt.Println(s.Count(), s.Peek())
+
+	// Output:
+	// 2 retry soon
+	// expired: retry soon
+	// 1 retry later
+}
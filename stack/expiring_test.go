@@ -0,0 +1,330 @@
+package stack
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock for Expiring stacks that only moves when told to.
+type fakeClock struct {
+	now   time.Time
+	mutex sync.Mutex
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Test_Expiring_Push tests that Expiring's Push and PushFor methods add values that expire at
+// their deadlines.
+func Test_Expiring_Push(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Expiring[int]
+		require.NotPanics(t, func() { s.Push(1, time.Time{}) })
+		require.NotPanics(t, func() { s.PushFor(1, time.Second) })
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("no deadline", func(t *testing.T) {
+		clock := newFakeClock()
+		s := Expiring[int]{Now: clock.Now}
+		s.Push(1, time.Time{})
+		s.Push(2, time.Time{})
+
+		clock.Advance(100 * 365 * 24 * time.Hour)
+		require.Equal(t, 2, s.Count())
+		require.Equal(t, 2, s.Pop())
+		require.Equal(t, 1, s.Pop())
+	})
+
+	t.Run("deadlines", func(t *testing.T) {
+		clock := newFakeClock()
+		s := Expiring[int]{Now: clock.Now}
+		s.Push(1, clock.Now().Add(3*time.Second))
+		s.PushFor(2, time.Second)
+		s.Push(3, time.Time{})
+		s.PushFor(4, 2*time.Second)
+		require.Equal(t, 4, s.Count())
+
+		clock.Advance(time.Second)
+		require.Equal(t, 3, s.Count())
+		require.Equal(t, 4, s.Peek())
+
+		clock.Advance(time.Second)
+		require.Equal(t, 2, s.Count())
+		require.Equal(t, 3, s.Peek())
+
+		clock.Advance(time.Second)
+		require.Equal(t, 1, s.Count())
+		require.Equal(t, 3, s.Pop())
+		require.True(t, s.Empty())
+	})
+
+	t.Run("already expired", func(t *testing.T) {
+		clock := newFakeClock()
+		var expired []int
+		s := Expiring[int]{
+			Now:      clock.Now,
+			OnExpire: func(v int, _ time.Time) { expired = append(expired, v) },
+		}
+		s.Push(1, clock.Now())
+		s.Push(2, clock.Now().Add(-time.Second))
+		s.PushFor(3, 0)
+		s.PushFor(4, -time.Second)
+
+		require.Equal(t, []int{1, 2, 3, 4}, expired)
+		require.True(t, s.Empty())
+	})
+
+	t.Run("system clock", func(t *testing.T) {
+		var s Expiring[int]
+		s.PushFor(1, time.Hour)
+		s.PushFor(2, time.Millisecond)
+		require.Eventually(t, func() bool { return s.Count() == 1 }, time.Second, time.Millisecond)
+		require.Equal(t, 1, s.Peek())
+	})
+}
+
+// Test_Expiring_Pop tests that Expiring's Pop and CheckPop methods skip expired values.
+func Test_Expiring_Pop(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Expiring[int]
+		require.Zero(t, s.Pop())
+
+		v, ok := s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, v)
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Expiring[int]
+		require.Zero(t, s.Pop())
+
+		v, ok := s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, v)
+	})
+
+	t.Run("skips expired values", func(t *testing.T) {
+		clock := newFakeClock()
+		s := Expiring[string]{Now: clock.Now}
+		s.Push("a", time.Time{})
+		s.PushFor("b", 2*time.Second)
+		s.PushFor("c", time.Second)
+		s.PushFor("d", time.Second)
+
+		clock.Advance(time.Second)
+		v, ok := s.CheckPop()
+		require.True(t, ok)
+		require.Equal(t, "b", v)
+
+		clock.Advance(time.Second)
+		v, ok = s.CheckPop()
+		require.True(t, ok)
+		require.Equal(t, "a", v)
+
+		v, ok = s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, v)
+	})
+
+	t.Run("only expired values", func(t *testing.T) {
+		clock := newFakeClock()
+		s := Expiring[int]{Now: clock.Now}
+		s.PushFor(1, time.Second)
+		s.PushFor(2, time.Second)
+
+		clock.Advance(time.Second)
+		v, ok := s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, v)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		clock := newFakeClock()
+		var mutex sync.Mutex
+		var expired int
+		s := Expiring[int]{
+			Now: clock.Now,
+			OnExpire: func(int, time.Time) {
+				mutex.Lock()
+				expired++
+				mutex.Unlock()
+			},
+		}
+
+		var popped sync.Map
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					s.PushFor(i*10+j, time.Duration(j)*time.Second)
+				}
+			}(i)
+			go func() {
+				defer wg.Done()
+				clock.Advance(time.Second / 10)
+				if v, ok := s.CheckPop(); ok {
+					_, dup := popped.LoadOrStore(v, true)
+					require.False(t, dup)
+				}
+			}()
+		}
+		wg.Wait()
+
+		clock.Advance(time.Hour)
+		require.Zero(t, s.Count())
+
+		var n int
+		popped.Range(func(any, any) bool {
+			n++
+			return true
+		})
+		require.Equal(t, 1000, n+expired)
+	})
+}
+
+// Test_Expiring_Peek tests that Expiring's Peek method skips expired values.
+func Test_Expiring_Peek(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Expiring[int]
+		require.Zero(t, s.Peek())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Expiring[int]
+		require.Zero(t, s.Peek())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		clock := newFakeClock()
+		s := Expiring[int]{Now: clock.Now}
+		s.Push(1, time.Time{})
+		s.PushFor(2, time.Second)
+		require.Equal(t, 2, s.Peek())
+		require.Equal(t, 2, s.Count())
+
+		clock.Advance(time.Second)
+		require.Equal(t, 1, s.Peek())
+		require.Equal(t, 1, s.Count())
+	})
+}
+
+// Test_Expiring_OnExpire tests that an Expiring stack reports the values it removes because they
+// expired, and only those.
+func Test_Expiring_OnExpire(t *testing.T) {
+	t.Run("reported once", func(t *testing.T) {
+		clock := newFakeClock()
+		type report struct {
+			v        int
+			deadline time.Time
+		}
+		var reports []report
+		s := Expiring[int]{
+			Now: clock.Now,
+			OnExpire: func(v int, deadline time.Time) {
+				reports = append(reports, report{v, deadline})
+			},
+		}
+
+		start := clock.Now()
+		s.PushFor(1, 2*time.Second)
+		s.PushFor(2, time.Second)
+		s.PushFor(3, 3*time.Second)
+		s.PushFor(4, time.Second)
+		s.Push(5, time.Time{})
+
+		clock.Advance(2 * time.Second)
+		require.Equal(t, 5, s.Pop())
+		require.Equal(t, []report{
+			{1, start.Add(2 * time.Second)},
+			{2, start.Add(time.Second)},
+			{4, start.Add(time.Second)},
+		}, reports)
+
+		require.Equal(t, 3, s.Pop())
+		require.Len(t, reports, 3)
+	})
+
+	t.Run("not reported when popped or cleared", func(t *testing.T) {
+		clock := newFakeClock()
+		var expired []int
+		s := Expiring[int]{
+			Now:      clock.Now,
+			OnExpire: func(v int, _ time.Time) { expired = append(expired, v) },
+		}
+		s.PushFor(1, time.Second)
+		s.PushFor(2, time.Second)
+		require.Equal(t, 2, s.Pop())
+
+		s.Clear()
+		clock.Advance(time.Second)
+		require.True(t, s.Empty())
+		require.Empty(t, expired)
+	})
+
+	t.Run("reentrant", func(t *testing.T) {
+		clock := newFakeClock()
+		var s Expiring[int]
+		s.Now = clock.Now
+		s.OnExpire = func(v int, _ time.Time) {
+			if v < 3 {
+				s.Push(v+1, time.Time{})
+			}
+		}
+		s.PushFor(1, time.Second)
+		s.PushFor(2, time.Second)
+
+		// The count is taken before the callbacks push their values.
+		clock.Advance(time.Second)
+		require.Zero(t, s.Count())
+		require.Equal(t, 2, s.Count())
+		require.Equal(t, 3, s.Pop())
+		require.Equal(t, 2, s.Pop())
+	})
+}
+
+// Test_Expiring_Purge tests that Expiring's Purge method removes expired values immediately.
+func Test_Expiring_Purge(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Expiring[int]
+		require.Zero(t, s.Purge())
+		require.NotPanics(t, func() { s.Clear() })
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		clock := newFakeClock()
+		s := Expiring[int]{Now: clock.Now}
+		for i := 0; i < 10; i++ {
+			s.PushFor(i, time.Duration(i%3)*time.Second+time.Second)
+		}
+		require.Zero(t, s.Purge())
+
+		clock.Advance(time.Second)
+		require.Equal(t, 4, s.Purge())
+		require.Len(t, s.entries, 6)
+		require.Equal(t, clock.Now().Add(time.Second), s.earliest)
+
+		clock.Advance(2 * time.Second)
+		require.Equal(t, 6, s.Purge())
+		require.Empty(t, s.entries)
+		require.True(t, s.earliest.IsZero())
+	})
+}
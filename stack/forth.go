@@ -0,0 +1,128 @@
+package stack
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTooShallow is returned when an operation needs more values than the stack holds.
+var ErrTooShallow = errors.New("stack: too shallow")
+
+// PeekAt returns the value at the given depth without removing it: depth 0 is the top of the stack,
+// depth 1 is the value beneath it, and so on. If the stack doesn't have a value at that depth, this
+// returns the zero value of the stack's type and an error wrapping ErrTooShallow.
+func (s *Stack[T]) PeekAt(depth int) (t T, err error) {
+	if depth < 0 {
+		return t, fmt.Errorf("stack: negative depth %d", depth)
+	}
+
+	if s == nil {
+		return t, tooShallow(depth+1, 0)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.need(depth + 1); err != nil {
+		return t, err
+	}
+
+	return s.items[len(s.items)-1-depth], nil
+}
+
+// Dup pushes a copy of the value at the top of the stack: ( a -- a a ).
+//
+// If the stack is empty, this returns an error wrapping ErrTooShallow. Because Dup adds a value, it
+// also fails with ErrClosed if the stack is closed and with ErrFull if the stack is bounded and
+// full (unless the stack evicts values on overflow).
+func (s *Stack[T]) Dup() error {
+	return s.copyToTop(0)
+}
+
+// Over pushes a copy of the second value from the top of the stack: ( a b -- a b a ). It fails in
+// the same ways as Dup, except that it needs at least two values.
+func (s *Stack[T]) Over() error {
+	return s.copyToTop(1)
+}
+
+// Swap exchanges the top two values on the stack: ( a b -- b a ). If the stack has fewer than two
+// values, this returns an error wrapping ErrTooShallow.
+func (s *Stack[T]) Swap() error {
+	return s.Roll(1)
+}
+
+// Rot moves the third value from the top of the stack to the top: ( a b c -- b c a ). If the stack
+// has fewer than three values, this returns an error wrapping ErrTooShallow.
+func (s *Stack[T]) Rot() error {
+	return s.Roll(2)
+}
+
+// Roll moves the value at the given depth to the top of the stack, shifting the values above it
+// down by one: Roll(0) does nothing, Roll(1) is Swap, and Roll(2) is Rot. If the stack doesn't have
+// a value at that depth, this returns an error wrapping ErrTooShallow.
+func (s *Stack[T]) Roll(depth int) error {
+	if depth < 0 {
+		return fmt.Errorf("stack: negative depth %d", depth)
+	}
+
+	if s == nil {
+		return tooShallow(depth+1, 0)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.need(depth + 1); err != nil {
+		return err
+	}
+
+	if depth == 0 {
+		return nil
+	}
+
+	i := len(s.items) - 1 - depth
+	s.notifyPopped(s.items[i:])
+
+	v := s.items[i]
+	copy(s.items[i:], s.items[i+1:])
+	s.items[len(s.items)-1] = v
+	s.notify(EventPushed, s.items[i:]...)
+	s.stats.touched()
+
+	return nil
+}
+
+// copyToTop pushes a copy of the value at the given depth.
+func (s *Stack[T]) copyToTop(depth int) error {
+	if s == nil {
+		return tooShallow(depth+1, 0)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.need(depth + 1); err != nil {
+		return err
+	}
+
+	return s.commit(&Tx[T]{
+		base:   s.items,
+		keep:   len(s.items),
+		pushed: []T{s.items[len(s.items)-1-depth]},
+	})
+}
+
+// need returns an error if the stack has fewer than n values. The caller must hold the stack's
+// lock.
+func (s *Stack[T]) need(n int) error {
+	if len(s.items) < n {
+		return tooShallow(n, len(s.items))
+	}
+
+	return nil
+}
+
+// tooShallow returns an error wrapping ErrTooShallow that describes how many values were needed.
+func tooShallow(need, have int) error {
+	return fmt.Errorf("%w: need %d, have %d", ErrTooShallow, need, have)
+}
@@ -0,0 +1,77 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleStack_PeekAt() {
+	var s stack.Stack[string]
+	s.PushMany("a", "b", "c")
+
+	v, err := s.PeekAt(2)
+	fmt.Println(v, err)
+
+	_, err = s.PeekAt(3)
+	fmt.Println(err)
+
+	// Output:
+	// a <nil>
+	// stack: too shallow: need 4, have 3
+}
+
+func ExampleStack_Dup() {
+	var s stack.Stack[int]
+	s.Push(7)
+	s.Dup()
+
+	fmt.Println(s.Drain())
+
+	// Output:
+	// [7 7]
+}
+
+func ExampleStack_Over() {
+	var s stack.Stack[int]
+	s.PushMany(1, 2)
+	s.Over()
+
+	fmt.Println(s.Drain())
+
+	// Output:
+	// [1 2 1]
+}
+
+func ExampleStack_Swap() {
+	var s stack.Stack[int]
+	s.PushMany(1, 2)
+	s.Swap()
+
+	fmt.Println(s.Drain())
+
+	// Output:
+	// [1 2]
+}
+
+func ExampleStack_Rot() {
+	var s stack.Stack[string]
+	s.PushMany("a", "b", "c")
+	s.Rot()
+
+	fmt.Println(s.Drain())
+
+	// Output:
+	// [a c b]
+}
+
+func ExampleStack_Roll() {
+	var s stack.Stack[int]
+	s.PushMany(1, 2, 3, 4)
+	s.Roll(3)
+
+	fmt.Println(s.Drain())
+
+	// Output:
+	// [1 4 3 2]
+}
@@ -0,0 +1,218 @@
+package stack
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Stack_PeekAt tests that Stack's PeekAt method returns the value at a given depth without
+// removing it for various stack configurations.
+func Test_Stack_PeekAt(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		v, err := s.PeekAt(0)
+		require.ErrorIs(t, err, ErrTooShallow)
+		require.Zero(t, v)
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		v, err := s.PeekAt(0)
+		require.ErrorIs(t, err, ErrTooShallow)
+		require.EqualError(t, err, "stack: too shallow: need 1, have 0")
+		require.Zero(t, v)
+	})
+
+	t.Run("negative depth", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		_, err := s.PeekAt(-1)
+		require.Error(t, err)
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b", "c")
+
+		for depth, want := range []string{"c", "b", "a"} {
+			v, err := s.PeekAt(depth)
+			require.NoError(t, err)
+			require.Equal(t, want, v)
+		}
+
+		_, err := s.PeekAt(3)
+		require.ErrorIs(t, err, ErrTooShallow)
+		require.Equal(t, 3, s.Count())
+	})
+}
+
+// Test_Stack_Dup tests that Stack's Dup method pushes a copy of the top value for various stack
+// configurations.
+func Test_Stack_Dup(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.ErrorIs(t, s.Dup(), ErrTooShallow)
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		require.ErrorIs(t, s.Dup(), ErrTooShallow)
+		require.True(t, s.Empty())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2)
+		require.NoError(t, s.Dup())
+		require.Equal(t, []int{2, 2, 1}, s.Drain())
+	})
+
+	t.Run("closed stack", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		s.Close()
+		require.ErrorIs(t, s.Dup(), ErrClosed)
+		require.Equal(t, 1, s.Count())
+	})
+
+	t.Run("full stack", func(t *testing.T) {
+		s := NewBounded[int](2, OverflowReject)
+		s.PushMany(1, 2)
+		require.ErrorIs(t, s.Dup(), ErrFull)
+
+		s = NewBounded[int](2, OverflowEvict)
+		s.PushMany(1, 2)
+		require.NoError(t, s.Dup())
+		require.Equal(t, []int{2, 2}, s.Drain())
+	})
+}
+
+// Test_Stack_Over tests that Stack's Over method pushes a copy of the second value for various
+// stack configurations.
+func Test_Stack_Over(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.ErrorIs(t, s.Over(), ErrTooShallow)
+	})
+
+	t.Run("too shallow", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		require.ErrorIs(t, s.Over(), ErrTooShallow)
+		require.Equal(t, 1, s.Count())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b")
+		require.NoError(t, s.Over())
+		require.Equal(t, []string{"a", "b", "a"}, s.Drain())
+	})
+}
+
+// Test_Stack_Swap tests that Stack's Swap method exchanges the top two values for various stack
+// configurations.
+func Test_Stack_Swap(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.ErrorIs(t, s.Swap(), ErrTooShallow)
+	})
+
+	t.Run("too shallow", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		require.ErrorIs(t, s.Swap(), ErrTooShallow)
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+		require.NoError(t, s.Swap())
+		require.Equal(t, []int{2, 3, 1}, s.Drain())
+	})
+
+	t.Run("closed stack", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2)
+		s.Close()
+		require.NoError(t, s.Swap())
+		require.Equal(t, []int{1, 2}, s.Drain())
+	})
+}
+
+// Test_Stack_Rot tests that Stack's Rot method moves the third value to the top for various stack
+// configurations.
+func Test_Stack_Rot(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.ErrorIs(t, s.Rot(), ErrTooShallow)
+	})
+
+	t.Run("too shallow", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2)
+		require.ErrorIs(t, s.Rot(), ErrTooShallow)
+		require.Equal(t, []int{2, 1}, s.Drain())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b", "c")
+		require.NoError(t, s.Rot())
+		require.Equal(t, []string{"a", "c", "b"}, s.Drain())
+	})
+}
+
+// Test_Stack_Roll tests that Stack's Roll method moves the value at a given depth to the top for
+// various stack configurations.
+func Test_Stack_Roll(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.ErrorIs(t, s.Roll(0), ErrTooShallow)
+	})
+
+	t.Run("negative depth", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		require.Error(t, s.Roll(-1))
+	})
+
+	t.Run("too shallow", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+		err := s.Roll(3)
+		require.ErrorIs(t, err, ErrTooShallow)
+		require.EqualError(t, err, "stack: too shallow: need 4, have 3")
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3, 4, 5)
+
+		require.NoError(t, s.Roll(0))
+		require.Equal(t, 5, s.Peek())
+
+		require.NoError(t, s.Roll(4))
+		require.Equal(t, []int{1, 5, 4, 3, 2}, s.Drain())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 300; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.NoError(t, s.Rot())
+			}()
+		}
+		wg.Wait()
+
+		// 300 rotations of three values is a full cycle.
+		require.Equal(t, []int{3, 2, 1}, s.Drain())
+	})
+}
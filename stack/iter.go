@@ -0,0 +1,61 @@
+package stack
+
+import "iter"
+
+// All returns an iterator over the stack's values from top to bottom, paired with their depth: the
+// top value has depth 0, the value beneath it has depth 1, and so on.
+//
+// The iterator works on a snapshot of the stack taken when iteration begins, so it is safe to use
+// while other goroutines push and pop, and the loop body can modify the stack freely. Changes made
+// after iteration begins are not visible to that iteration. If the stack is nil, the iterator
+// yields nothing.
+func (s *Stack[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		items := s.snapshot()
+		for i := len(items) - 1; i >= 0; i-- {
+			if !yield(len(items)-1-i, items[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the stack's values from bottom to top, paired with their
+// position: the bottom value has position 0, the value above it has position 1, and so on. It has
+// the same snapshot semantics as All.
+func (s *Stack[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, v := range s.snapshot() {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the stack's values from top to bottom. It has the same snapshot
+// semantics as All.
+func (s *Stack[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range s.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// snapshot returns a copy of the stack's values from bottom to top, or nil if the stack is nil.
+func (s *Stack[T]) snapshot() []T {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	items := make([]T, len(s.items))
+	copy(items, s.items)
+
+	return items
+}
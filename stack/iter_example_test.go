@@ -0,0 +1,55 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleStack_All() {
+	var s stack.Stack[string]
+	s.Push("a")
+	s.Push("b")
+	s.Push("c")
+
+	for depth, v := range s.All() {
+		fmt.Println(depth, v)
+	}
+
+	// Output:
+	// 0 c
+	// 1 b
+	// 2 a
+}
+
+func ExampleStack_Backward() {
+	var s stack.Stack[string]
+	s.Push("a")
+	s.Push("b")
+	s.Push("c")
+
+	for pos, v := range s.Backward() {
+		fmt.Println(pos, v)
+	}
+
+	// Output:
+	// 0 a
+	// 1 b
+	// 2 c
+}
+
+func ExampleStack_Values() {
+	var s stack.Stack[int]
+	for i := 1; i <= 3; i++ {
+		s.Push(i)
+	}
+
+	for v := range s.Values() {
+		fmt.Println(v)
+	}
+
+	// Output:
+	// 3
+	// 2
+	// 1
+}
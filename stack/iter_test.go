@@ -0,0 +1,185 @@
+package stack
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Stack_All tests that Stack's All method iterates over the stack from top to bottom for
+// various stack configurations.
+func Test_Stack_All(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		for range s.All() {
+			t.Fatal("unexpected value")
+		}
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		for range s.All() {
+			t.Fatal("unexpected value")
+		}
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.Push("a")
+		s.Push("b")
+		s.Push("c")
+
+		var depths []int
+		var values []string
+		for depth, v := range s.All() {
+			depths = append(depths, depth)
+			values = append(values, v)
+		}
+		require.Equal(t, []int{0, 1, 2}, depths)
+		require.Equal(t, []string{"c", "b", "a"}, values)
+		require.Equal(t, 3, s.Count())
+	})
+
+	t.Run("early exit", func(t *testing.T) {
+		var s Stack[int]
+		for i := 0; i < 10; i++ {
+			s.Push(i)
+		}
+
+		var values []int
+		for _, v := range s.All() {
+			if v < 7 {
+				break
+			}
+			values = append(values, v)
+		}
+		require.Equal(t, []int{9, 8, 7}, values)
+	})
+
+	t.Run("modify during iteration", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		s.Push(2)
+
+		var values []int
+		for _, v := range s.All() {
+			s.Push(v * 10)
+			s.Pop()
+			s.Pop()
+			values = append(values, v)
+		}
+		require.Equal(t, []int{2, 1}, values)
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				s.Push(i)
+				s.Pop()
+			}(i)
+			go func() {
+				defer wg.Done()
+				prev := -1
+				for depth := range s.All() {
+					require.Equal(t, prev+1, depth)
+					prev = depth
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+// Test_Stack_Backward tests that Stack's Backward method iterates over the stack from bottom to top
+// for various stack configurations.
+func Test_Stack_Backward(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		for range s.Backward() {
+			t.Fatal("unexpected value")
+		}
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		for range s.Backward() {
+			t.Fatal("unexpected value")
+		}
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.Push("a")
+		s.Push("b")
+		s.Push("c")
+
+		var positions []int
+		var values []string
+		for pos, v := range s.Backward() {
+			positions = append(positions, pos)
+			values = append(values, v)
+		}
+		require.Equal(t, []int{0, 1, 2}, positions)
+		require.Equal(t, []string{"a", "b", "c"}, values)
+	})
+
+	t.Run("early exit", func(t *testing.T) {
+		var s Stack[int]
+		for i := 0; i < 10; i++ {
+			s.Push(i)
+		}
+
+		var values []int
+		for _, v := range s.Backward() {
+			if v > 2 {
+				break
+			}
+			values = append(values, v)
+		}
+		require.Equal(t, []int{0, 1, 2}, values)
+	})
+}
+
+// Test_Stack_Values tests that Stack's Values method iterates over the stack's values from top to
+// bottom for various stack configurations.
+func Test_Stack_Values(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		for range s.Values() {
+			t.Fatal("unexpected value")
+		}
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[rune]
+		for _, r := range "abc" {
+			s.Push(r)
+		}
+
+		var values []rune
+		for v := range s.Values() {
+			values = append(values, v)
+		}
+		require.Equal(t, []rune{'c', 'b', 'a'}, values)
+	})
+
+	t.Run("early exit", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		s.Push(2)
+
+		var values []int
+		for v := range s.Values() {
+			values = append(values, v)
+			break
+		}
+		require.Equal(t, []int{2}, values)
+	})
+}
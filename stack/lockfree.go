@@ -0,0 +1,119 @@
+package stack
+
+import "sync/atomic"
+
+// A LockFree stack is a first-in-last-out (FILO) data structure that is safe for concurrent use
+// without any locks. Every operation is a single atomic load or compare-and-swap on the top of the
+// stack, which makes it scale better than Stack when many goroutines push and pop at once. The zero
+// value is an empty stack and ready to use.
+//
+// Unlike Stack, a LockFree stack is unbounded, cannot be closed, and has no blocking operations.
+type LockFree[T any] struct {
+	top atomic.Pointer[node[T]]
+}
+
+// A node is one element of a lock-free stack. Nodes are never modified after they are published,
+// and a new node is allocated for every push. Because the garbage collector won't reuse a node's
+// memory while any goroutine still holds a pointer to it, a compare-and-swap that sees the same
+// top pointer is guaranteed to see the same node, which rules out the ABA problem.
+type node[T any] struct {
+	value T
+	next  *node[T]
+
+	// depth is the number of nodes in the list starting at (and including) this one.
+	depth int
+}
+
+// Push adds a value to the top of the stack.
+func (s *LockFree[T]) Push(v T) {
+	if s == nil {
+		return
+	}
+
+	n := &node[T]{value: v}
+	for {
+		top := s.top.Load()
+		n.next = top
+		n.depth = top.count() + 1
+
+		if s.top.CompareAndSwap(top, n) {
+			return
+		}
+	}
+}
+
+// Pop removes and returns the value at the top of the stack. If the stack is empty, this returns
+// the zero value of the stack's type.
+func (s *LockFree[T]) Pop() (t T) {
+	t, _ = s.CheckPop()
+	return t
+}
+
+// CheckPop returns the value at the top of the stack and a boolean indicating whether the stack is
+// empty. If the stack is empty, this returns the zero value of the stack's type and false.
+func (s *LockFree[T]) CheckPop() (t T, ok bool) {
+	if s == nil {
+		return
+	}
+
+	for {
+		top := s.top.Load()
+		if top == nil {
+			return
+		}
+
+		if s.top.CompareAndSwap(top, top.next) {
+			return top.value, true
+		}
+	}
+}
+
+// Peek returns the value at the top of the stack without removing it. If the stack is empty, this
+// returns the zero value of the stack's type.
+func (s *LockFree[T]) Peek() (t T) {
+	if s == nil {
+		return
+	}
+
+	if top := s.top.Load(); top != nil {
+		return top.value
+	}
+
+	return
+}
+
+// Empty returns true if the stack is empty.
+func (s *LockFree[T]) Empty() bool {
+	if s == nil {
+		return true
+	}
+
+	return s.top.Load() == nil
+}
+
+// Count returns the number of elements in the stack.
+func (s *LockFree[T]) Count() int {
+	if s == nil {
+		return 0
+	}
+
+	return s.top.Load().count()
+}
+
+// Clear removes all elements from the stack.
+func (s *LockFree[T]) Clear() {
+	if s == nil {
+		return
+	}
+
+	s.top.Store(nil)
+}
+
+// count returns the number of nodes in the list starting at n.
+func (n *node[T]) count() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.depth
+}
@@ -0,0 +1,26 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleLockFree() {
+	var s stack.LockFree[string]
+	s.Push("hello")
+	s.Push("world")
+
+	fmt.Println(s.Count())
+	fmt.Println(s.Pop())
+	fmt.Println(s.Pop())
+
+	top, ok := s.CheckPop()
+	fmt.Printf("%q %v\n", top, ok)
+
+	// Output:
+	// 2
+	// world
+	// hello
+	// "" false
+}
@@ -0,0 +1,312 @@
+package stack
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_LockFree_Push tests that LockFree's Push method adds a value to the top of the stack for
+// various stack configurations.
+func Test_LockFree_Push(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *LockFree[int32]
+		require.NotPanics(t, func() { s.Push(1) })
+		require.NotPanics(t, func() { s.Push(1) })
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s LockFree[float32]
+		require.NotPanics(t, func() { s.Push(1) })
+		require.Equal(t, 1, s.Count())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s LockFree[string]
+		s.Push("1")
+		s.Push("2")
+		s.Push("3")
+		require.Equal(t, 3, s.Count())
+		require.Equal(t, "3", s.Pop())
+		require.Equal(t, "2", s.Pop())
+		require.Equal(t, "1", s.Pop())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s LockFree[int]
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.Push(i)
+			}(i)
+		}
+
+		wg.Wait()
+		require.Equal(t, 100, s.Count())
+	})
+}
+
+// Test_LockFree_Pop tests that LockFree's Pop method removes and returns the value at the top of
+// the stack for various stack configurations.
+func Test_LockFree_Pop(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *LockFree[int32]
+		require.Zero(t, s.Pop())
+		require.Zero(t, s.Pop())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s LockFree[rune]
+		require.Zero(t, s.Pop())
+		require.Zero(t, s.Pop())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s LockFree[int]
+		for i := 1; i <= 10; i++ {
+			s.Push(i)
+		}
+		for i := 10; i >= 1; i-- {
+			require.Equal(t, i, s.Pop())
+			require.Equal(t, i-1, s.Count())
+		}
+		require.Zero(t, s.Pop())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s LockFree[int]
+
+		var want []int
+		for i := 0; i < 100; i++ {
+			s.Push(i)
+			want = append(want, i)
+		}
+
+		ch := make(chan int, 100)
+		for i := 0; i < 100; i++ {
+			go func() {
+				ch <- s.Pop()
+			}()
+		}
+
+		var have []int
+		for i := 0; i < 100; i++ {
+			have = append(have, <-ch)
+		}
+		sort.Ints(have)
+		require.Equal(t, want, have)
+		require.Zero(t, s.Pop())
+	})
+}
+
+// Test_LockFree_CheckPop tests that LockFree's CheckPop method returns the value at the top of the
+// stack and a boolean indicating whether the stack is empty for various stack configurations.
+func Test_LockFree_CheckPop(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *LockFree[uint8]
+		top, ok := s.CheckPop()
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s LockFree[string]
+		top, ok := s.CheckPop()
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s LockFree[int]
+		s.Push(0)
+		s.Push(1)
+
+		top, ok := s.CheckPop()
+		require.Equal(t, 1, top)
+		require.True(t, ok)
+
+		top, ok = s.CheckPop()
+		require.Equal(t, 0, top)
+		require.True(t, ok)
+
+		top, ok = s.CheckPop()
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+}
+
+// Test_LockFree_Peek tests that LockFree's Peek method returns the value at the top of the stack
+// without removing it for various stack configurations.
+func Test_LockFree_Peek(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *LockFree[[]rune]
+		require.Zero(t, s.Peek())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s LockFree[[]any]
+		require.Zero(t, s.Peek())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s LockFree[rune]
+		s.Push('a')
+		s.Push('b')
+		require.Equal(t, 'b', s.Peek())
+		require.Equal(t, 'b', s.Peek())
+		require.Equal(t, 2, s.Count())
+	})
+}
+
+// Test_LockFree_Empty tests that LockFree's Empty method accurately determines if the stack is
+// empty for various stack configurations.
+func Test_LockFree_Empty(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *LockFree[bool]
+		require.True(t, s.Empty())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s LockFree[bool]
+		require.True(t, s.Empty())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s LockFree[bool]
+		s.Push(false)
+		require.False(t, s.Empty())
+		s.Pop()
+		require.True(t, s.Empty())
+	})
+}
+
+// Test_LockFree_Count tests that LockFree's Count method returns the correct number of elements
+// in the stack for various stack configurations.
+func Test_LockFree_Count(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *LockFree[int]
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s LockFree[int]
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s LockFree[string]
+		s.Push("a")
+		require.Equal(t, 1, s.Count())
+		s.Push("b")
+		require.Equal(t, 2, s.Count())
+		s.Pop()
+		require.Equal(t, 1, s.Count())
+	})
+}
+
+// Test_LockFree_Clear tests that LockFree's Clear method removes all elements from the stack for
+// various stack configurations.
+func Test_LockFree_Clear(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *LockFree[int32]
+		require.NotPanics(t, func() { s.Clear() })
+		require.True(t, s.Empty())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s LockFree[bool]
+		require.NotPanics(t, func() { s.Clear() })
+		require.True(t, s.Empty())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s LockFree[string]
+		s.Push("a")
+		s.Push("b")
+		s.Clear()
+		require.True(t, s.Empty())
+		require.Zero(t, s.Count())
+
+		s.Push("c")
+		require.Equal(t, 1, s.Count())
+		require.Equal(t, "c", s.Pop())
+	})
+}
+
+// Test_LockFree_ConcurrentUse tests that every value pushed onto a LockFree stack by many
+// goroutines is popped exactly once.
+func Test_LockFree_ConcurrentUse(t *testing.T) {
+	var s LockFree[int]
+
+	const goroutines, perGoroutine = 16, 10_000
+
+	var wg sync.WaitGroup
+	results := make([][]int, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				s.Push(g*perGoroutine + i)
+				if v, ok := s.CheckPop(); ok {
+					results[g] = append(results[g], v)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for v, ok := s.CheckPop(); ok; v, ok = s.CheckPop() {
+		results[0] = append(results[0], v)
+	}
+
+	seen := make([]bool, goroutines*perGoroutine)
+	for _, r := range results {
+		for _, v := range r {
+			require.False(t, seen[v], "value %d popped twice", v)
+			seen[v] = true
+		}
+	}
+	for v, ok := range seen {
+		require.True(t, ok, "value %d never popped", v)
+	}
+}
+
+// Benchmark_Contention compares Stack, LockFree, and Elimination when many goroutines push and pop at once.
+func Benchmark_Contention(b *testing.B) {
+	b.Run("Stack", func(b *testing.B) {
+		var s Stack[int]
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				s.Push(i)
+				s.Pop()
+			}
+		})
+	})
+
+	b.Run("LockFree", func(b *testing.B) {
+		var s LockFree[int]
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				s.Push(i)
+				s.Pop()
+			}
+		})
+	})
+
+	b.Run("Elimination", func(b *testing.B) {
+		var s Elimination[int]
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				s.Push(i)
+				s.Pop()
+			}
+		})
+	})
+}
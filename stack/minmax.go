@@ -0,0 +1,174 @@
+package stack
+
+import (
+	"cmp"
+	"sync"
+)
+
+// A MinMax stack is a first-in-last-out (FILO) data structure that also tracks the smallest and
+// largest values on it. Every entry records the extremes of the stack beneath and including it, so
+// Min and Max are O(1) no matter how the stack changes. A MinMax stack is safe for concurrent use.
+//
+// Create a MinMax stack with NewMinMax or NewOrderedMinMax. The zero value has no comparison
+// function, so it behaves like an ordinary stack whose Min and Max never report a value.
+type MinMax[T any] struct {
+	entries []minMaxEntry[T]
+	cmp     func(a, b T) int
+	mutex   sync.Mutex
+}
+
+// A minMaxEntry is a value on a MinMax stack, along with the smallest and largest values at or
+// below it.
+type minMaxEntry[T any] struct {
+	value T
+	min   T
+	max   T
+}
+
+// NewMinMax returns a new, empty MinMax stack that orders values with cmp, which follows the same
+// convention as cmp.Compare: it returns a negative number if a < b, zero if a == b, and a positive
+// number if a > b.
+func NewMinMax[T any](cmp func(a, b T) int) *MinMax[T] {
+	return &MinMax[T]{
+		cmp: cmp,
+	}
+}
+
+// NewOrderedMinMax returns a new, empty MinMax stack that orders values with cmp.Compare.
+func NewOrderedMinMax[T cmp.Ordered]() *MinMax[T] {
+	return NewMinMax(cmp.Compare[T])
+}
+
+// Push adds a value to the top of the stack.
+func (s *MinMax[T]) Push(v T) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e := minMaxEntry[T]{value: v, min: v, max: v}
+	if n := len(s.entries); n > 0 && s.cmp != nil {
+		below := s.entries[n-1]
+		if s.cmp(below.min, v) <= 0 {
+			e.min = below.min
+		}
+		if s.cmp(below.max, v) >= 0 {
+			e.max = below.max
+		}
+	}
+
+	s.entries = append(s.entries, e)
+}
+
+// Pop removes and returns the value at the top of the stack. If the stack is empty, this returns
+// the zero value of the stack's type.
+func (s *MinMax[T]) Pop() (t T) {
+	t, _ = s.CheckPop()
+	return t
+}
+
+// CheckPop returns the value at the top of the stack and a boolean indicating whether the stack is
+// empty. If the stack is empty, this returns the zero value of the stack's type and false.
+func (s *MinMax[T]) CheckPop() (t T, ok bool) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	n := len(s.entries)
+	if n == 0 {
+		return
+	}
+
+	t = s.entries[n-1].value
+	s.entries[n-1] = minMaxEntry[T]{}
+	s.entries = s.entries[:n-1]
+
+	return t, true
+}
+
+// Peek returns the value at the top of the stack without removing it. If the stack is empty, this
+// returns the zero value of the stack's type.
+func (s *MinMax[T]) Peek() (t T) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if n := len(s.entries); n > 0 {
+		return s.entries[n-1].value
+	}
+
+	return
+}
+
+// Min returns the smallest value on the stack and true. If several values are equally small, this
+// returns the one closest to the bottom of the stack. If the stack is empty or has no comparison
+// function, this returns the zero value of the stack's type and false.
+func (s *MinMax[T]) Min() (t T, ok bool) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if n := len(s.entries); n > 0 && s.cmp != nil {
+		return s.entries[n-1].min, true
+	}
+
+	return
+}
+
+// Max returns the largest value on the stack and true. If several values are equally large, this
+// returns the one closest to the bottom of the stack. If the stack is empty or has no comparison
+// function, this returns the zero value of the stack's type and false.
+func (s *MinMax[T]) Max() (t T, ok bool) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if n := len(s.entries); n > 0 && s.cmp != nil {
+		return s.entries[n-1].max, true
+	}
+
+	return
+}
+
+// Empty returns true if the stack is empty.
+func (s *MinMax[T]) Empty() bool {
+	return s.Count() == 0
+}
+
+// Count returns the number of elements in the stack.
+func (s *MinMax[T]) Count() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.entries)
+}
+
+// Clear removes all elements from the stack.
+func (s *MinMax[T]) Clear() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries = nil
+}
@@ -0,0 +1,29 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleMinMax() {
+	s := stack.NewOrderedMinMax[int]()
+	for _, v := range []int{5, 3, 8} {
+		s.Push(v)
+	}
+
+	least, _ := s.Min()
+	most, _ := s.Max()
+	fmt.Println(least, most)
+
+	s.Pop()
+	s.Pop()
+
+	least, _ = s.Min()
+	most, _ = s.Max()
+	fmt.Println(least, most)
+
+	// Output:
+	// 3 8
+	// 5 5
+}
@@ -0,0 +1,203 @@
+package stack
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_MinMax_Push tests that MinMax's Push method adds a value to the top of the stack and updates
+// the extremes for various stack configurations.
+func Test_MinMax_Push(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *MinMax[int]
+		require.NotPanics(t, func() { s.Push(1) })
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("zero stack", func(t *testing.T) {
+		var s MinMax[int]
+		s.Push(2)
+		s.Push(1)
+		require.Equal(t, 2, s.Count())
+		require.Equal(t, 1, s.Peek())
+
+		_, ok := s.Min()
+		require.False(t, ok)
+		_, ok = s.Max()
+		require.False(t, ok)
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		s := NewOrderedMinMax[int]()
+		for _, v := range []int{5, 3, 8, 1, 9} {
+			s.Push(v)
+		}
+		require.Equal(t, 5, s.Count())
+		require.Equal(t, 9, s.Peek())
+
+		least, ok := s.Min()
+		require.True(t, ok)
+		require.Equal(t, 1, least)
+
+		most, ok := s.Max()
+		require.True(t, ok)
+		require.Equal(t, 9, most)
+	})
+
+	t.Run("custom comparison", func(t *testing.T) {
+		s := NewMinMax(func(a, b string) int { return cmp.Compare(len(a), len(b)) })
+		s.Push("ccc")
+		s.Push("a")
+		s.Push("bb")
+		s.Push("zzz")
+
+		least, _ := s.Min()
+		require.Equal(t, "a", least)
+
+		// Ties go to the value closest to the bottom.
+		most, _ := s.Max()
+		require.Equal(t, "ccc", most)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		s := NewOrderedMinMax[int]()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.Push(i)
+			}(i)
+		}
+		wg.Wait()
+
+		least, _ := s.Min()
+		most, _ := s.Max()
+		require.Equal(t, 0, least)
+		require.Equal(t, 99, most)
+	})
+}
+
+// Test_MinMax_CheckPop tests that MinMax's Pop and CheckPop methods remove the value at the top of
+// the stack and restore the previous extremes for various stack configurations.
+func Test_MinMax_CheckPop(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *MinMax[int]
+		require.Zero(t, s.Pop())
+
+		top, ok := s.CheckPop()
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		s := NewOrderedMinMax[int]()
+		require.Zero(t, s.Pop())
+
+		top, ok := s.CheckPop()
+		require.Zero(t, top)
+		require.False(t, ok)
+
+		_, ok = s.Min()
+		require.False(t, ok)
+		_, ok = s.Max()
+		require.False(t, ok)
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		s := NewOrderedMinMax[int]()
+		for _, v := range []int{5, 3, 8, 1, 9} {
+			s.Push(v)
+		}
+
+		for _, want := range []struct{ top, min, max int }{
+			{9, 1, 9},
+			{1, 1, 8},
+			{8, 3, 8},
+			{3, 3, 5},
+		} {
+			least, _ := s.Min()
+			most, _ := s.Max()
+			require.Equal(t, want.min, least)
+			require.Equal(t, want.max, most)
+
+			top, ok := s.CheckPop()
+			require.True(t, ok)
+			require.Equal(t, want.top, top)
+		}
+
+		least, _ := s.Min()
+		most, _ := s.Max()
+		require.Equal(t, 5, least)
+		require.Equal(t, 5, most)
+		require.Equal(t, 5, s.Pop())
+		require.True(t, s.Empty())
+	})
+
+	t.Run("matches brute force", func(t *testing.T) {
+		s := NewOrderedMinMax[int]()
+		var model []int
+
+		for i := 0; i < 10_000; i++ {
+			if len(model) == 0 || rand.IntN(3) > 0 {
+				v := rand.IntN(1000)
+				s.Push(v)
+				model = append(model, v)
+			} else {
+				require.Equal(t, model[len(model)-1], s.Pop())
+				model = model[:len(model)-1]
+			}
+
+			least, ok := s.Min()
+			require.Equal(t, len(model) > 0, ok)
+			most, _ := s.Max()
+			if len(model) > 0 {
+				require.Equal(t, slices.Min(model), least)
+				require.Equal(t, slices.Max(model), most)
+			}
+		}
+	})
+}
+
+// Test_MinMax_Peek tests that MinMax's Peek, Empty, Count, and Clear methods report and reset the
+// stack's state for various stack configurations.
+func Test_MinMax_Peek(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *MinMax[string]
+		require.Zero(t, s.Peek())
+		require.True(t, s.Empty())
+		require.Zero(t, s.Count())
+		require.NotPanics(t, func() { s.Clear() })
+
+		_, ok := s.Min()
+		require.False(t, ok)
+		_, ok = s.Max()
+		require.False(t, ok)
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		s := NewMinMax(strings.Compare)
+		s.Push("b")
+		s.Push("a")
+		require.Equal(t, "a", s.Peek())
+		require.False(t, s.Empty())
+		require.Equal(t, 2, s.Count())
+
+		s.Clear()
+		require.Zero(t, s.Peek())
+		require.True(t, s.Empty())
+		_, ok := s.Min()
+		require.False(t, ok)
+
+		s.Push("c")
+		least, _ := s.Min()
+		require.Equal(t, "c", least)
+	})
+}
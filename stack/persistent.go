@@ -0,0 +1,89 @@
+package stack
+
+// A Persistent stack is an immutable first-in-last-out (FILO) data structure. Pushing onto or
+// popping from a persistent stack doesn't change it; instead, it returns a new version that shares
+// all of its unchanged values with the old one. Every version stays valid, so a persistent stack
+// is a cheap way to keep snapshots of a stack's history. Push, Pop, Peek, and Count are all O(1).
+//
+// The zero value is an empty stack and ready to use. Persistent stacks are values and should be
+// passed around by value. Because they never change, they are safe for concurrent use.
+//
+// Comparing persistent stacks with == is O(1) and compares identity, not contents: two stacks are
+// == only if they are the same version, such as when one was derived from the other by operations
+// that cancel out (like popping a value that was just pushed), or if both are empty. Stacks that
+// hold equal values but were built separately are not ==.
+type Persistent[T any] struct {
+	top *node[T]
+}
+
+// Push returns a new version of the stack with a value added to the top.
+func (p Persistent[T]) Push(v T) Persistent[T] {
+	return Persistent[T]{
+		top: &node[T]{
+			value: v,
+			next:  p.top,
+			depth: p.top.count() + 1,
+		},
+	}
+}
+
+// Pop returns the value at the top of the stack and a new version of the stack without it. If the
+// stack is empty, this returns the zero value of the stack's type and the empty stack.
+func (p Persistent[T]) Pop() (t T, rest Persistent[T]) {
+	t, rest, _ = p.CheckPop()
+	return t, rest
+}
+
+// CheckPop returns the value at the top of the stack, a new version of the stack without it, and a
+// boolean indicating whether the stack is empty. If the stack is empty, this returns the zero value
+// of the stack's type, the empty stack, and false.
+func (p Persistent[T]) CheckPop() (t T, rest Persistent[T], ok bool) {
+	if p.top == nil {
+		return
+	}
+
+	return p.top.value, Persistent[T]{top: p.top.next}, true
+}
+
+// Peek returns the value at the top of the stack. If the stack is empty, this returns the zero
+// value of the stack's type.
+func (p Persistent[T]) Peek() (t T) {
+	if p.top == nil {
+		return
+	}
+
+	return p.top.value
+}
+
+// Empty returns true if the stack is empty.
+func (p Persistent[T]) Empty() bool {
+	return p.top == nil
+}
+
+// Count returns the number of elements in the stack.
+func (p Persistent[T]) Count() int {
+	return p.top.count()
+}
+
+// Stack returns a new Stack holding the same values as the persistent stack, in the same order.
+func (p Persistent[T]) Stack() *Stack[T] {
+	items := make([]T, p.Count())
+	for n, i := p.top, len(items)-1; n != nil; n, i = n.next, i-1 {
+		items[i] = n.value
+	}
+
+	return &Stack[T]{
+		items: items,
+	}
+}
+
+// Persistent returns a persistent stack holding the stack's current values, in the same order.
+// Later changes to the stack don't affect the persistent stack, and vice versa.
+func (s *Stack[T]) Persistent() Persistent[T] {
+	var p Persistent[T]
+	for _, v := range s.snapshot() {
+		p = p.Push(v)
+	}
+
+	return p
+}
@@ -0,0 +1,39 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExamplePersistent() {
+	var v1 stack.Persistent[string]
+	v2 := v1.Push("a")
+	v3 := v2.Push("b")
+
+	top, v4 := v3.Pop()
+
+	fmt.Println(v1.Count(), v2.Count(), v3.Count(), v4.Count())
+	fmt.Println(top, v3.Peek(), v4.Peek())
+	fmt.Println(v4 == v2)
+
+	// Output:
+	// 0 1 2 1
+	// b b a
+	// true
+}
+
+func ExampleStack_Persistent() {
+	var s stack.Stack[int]
+	s.PushMany(1, 2, 3)
+
+	snapshot := s.Persistent()
+	s.Clear()
+
+	fmt.Println(s.Count(), snapshot.Count())
+	fmt.Println(snapshot.Stack().Drain())
+
+	// Output:
+	// 0 3
+	// [3 2 1]
+}
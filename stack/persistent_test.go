@@ -0,0 +1,145 @@
+package stack
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Persistent_Push tests that Persistent's Push method returns a new version of the stack and
+// leaves the old version unchanged.
+func Test_Persistent_Push(t *testing.T) {
+	t.Run("zero stack", func(t *testing.T) {
+		var p Persistent[int]
+		p2 := p.Push(1)
+		require.True(t, p.Empty())
+		require.Equal(t, 1, p2.Count())
+		require.Equal(t, 1, p2.Peek())
+	})
+
+	t.Run("structural sharing", func(t *testing.T) {
+		base := Persistent[string]{}.Push("a").Push("b")
+		left := base.Push("c")
+		right := base.Push("d")
+
+		require.Equal(t, 2, base.Count())
+		require.Equal(t, "c", left.Peek())
+		require.Equal(t, "d", right.Peek())
+
+		_, leftRest := left.Pop()
+		_, rightRest := right.Pop()
+		require.True(t, leftRest == base)
+		require.True(t, rightRest == base)
+		require.Same(t, base.top, left.top.next)
+	})
+}
+
+// Test_Persistent_CheckPop tests that Persistent's Pop and CheckPop methods return the top value and
+// the rest of the stack without changing the original.
+func Test_Persistent_CheckPop(t *testing.T) {
+	t.Run("zero stack", func(t *testing.T) {
+		var p Persistent[int]
+		top, rest := p.Pop()
+		require.Zero(t, top)
+		require.True(t, rest.Empty())
+
+		top, rest, ok := p.CheckPop()
+		require.Zero(t, top)
+		require.True(t, rest.Empty())
+		require.False(t, ok)
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var p Persistent[int]
+		for i := 1; i <= 5; i++ {
+			p = p.Push(i)
+		}
+
+		q := p
+		for i := 5; i >= 1; i-- {
+			var top int
+			var ok bool
+			top, q, ok = q.CheckPop()
+			require.True(t, ok)
+			require.Equal(t, i, top)
+			require.Equal(t, i-1, q.Count())
+		}
+		require.True(t, q.Empty())
+
+		// The original version is untouched.
+		require.Equal(t, 5, p.Count())
+		require.Equal(t, 5, p.Peek())
+	})
+}
+
+// Test_Persistent_equality tests that persistent stacks compare equal only when they are the same
+// version.
+func Test_Persistent_equality(t *testing.T) {
+	var empty Persistent[int]
+	require.True(t, empty == Persistent[int]{})
+
+	a := empty.Push(1)
+	b := empty.Push(1)
+	require.False(t, a == b)
+	require.True(t, a == a)
+
+	_, rest := a.Push(2).Pop()
+	require.True(t, rest == a)
+
+	_, rest = a.Pop()
+	require.True(t, rest == empty)
+}
+
+// Test_Persistent_Stack tests that persistent stacks convert to and from Stack without sharing any
+// state.
+func Test_Persistent_Stack(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		var p Persistent[int]
+		s := p.Stack()
+		require.True(t, s.Empty())
+		s.Push(1)
+		require.True(t, p.Empty())
+
+		var nilStack *Stack[int]
+		require.True(t, nilStack.Persistent().Empty())
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b", "c")
+
+		p := s.Persistent()
+		require.Equal(t, 3, p.Count())
+		require.Equal(t, "c", p.Peek())
+
+		s.Pop()
+		require.Equal(t, 3, p.Count())
+
+		s2 := p.Stack()
+		require.Equal(t, []string{"c", "b", "a"}, s2.Drain())
+		require.Equal(t, 3, p.Count())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var p Persistent[int]
+		for i := 0; i < 100; i++ {
+			p = p.Push(i)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				q := p.Push(-i)
+				require.Equal(t, 101, q.Count())
+				s := q.Stack()
+				require.Equal(t, -i, s.Pop())
+				require.Equal(t, 99, s.Pop())
+			}(i)
+		}
+		wg.Wait()
+		require.Equal(t, 100, p.Count())
+	})
+}
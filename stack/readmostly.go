@@ -0,0 +1,99 @@
+package stack
+
+import "sync"
+
+// A ReadMostly stack is a first-in-last-out (FILO) data structure for workloads where Peek, Empty,
+// and Count far outnumber pushes and pops. Its read-only methods share a read-write lock, so they
+// run in parallel with each other and only wait for pushes and pops. In exchange, pushes and pops
+// cost more than they do on a Stack, even without contention. The zero value is an empty stack and
+// ready to use. A ReadMostly stack is safe for concurrent use.
+type ReadMostly[T any] struct {
+	items []T
+	mutex sync.RWMutex
+}
+
+// Push adds a value to the top of the stack.
+func (s *ReadMostly[T]) Push(v T) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.items = append(s.items, v)
+}
+
+// Pop removes and returns the value at the top of the stack. If the stack is empty, this returns
+// the zero value of the stack's type.
+func (s *ReadMostly[T]) Pop() (t T) {
+	t, _ = s.CheckPop()
+	return t
+}
+
+// CheckPop returns the value at the top of the stack and a boolean indicating whether the stack is
+// empty. If the stack is empty, this returns the zero value of the stack's type and false.
+func (s *ReadMostly[T]) CheckPop() (t T, ok bool) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	n := len(s.items)
+	if n == 0 {
+		return
+	}
+
+	t, s.items[n-1] = s.items[n-1], t
+	s.items = s.items[:n-1]
+
+	return t, true
+}
+
+// Peek returns the value at the top of the stack without removing it. If the stack is empty, this
+// returns the zero value of the stack's type.
+func (s *ReadMostly[T]) Peek() (t T) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if n := len(s.items); n > 0 {
+		return s.items[n-1]
+	}
+
+	return
+}
+
+// Empty returns true if the stack is empty.
+func (s *ReadMostly[T]) Empty() bool {
+	return s.Count() == 0
+}
+
+// Count returns the number of elements in the stack.
+func (s *ReadMostly[T]) Count() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.items)
+}
+
+// Clear removes all elements from the stack.
+func (s *ReadMostly[T]) Clear() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.items = nil
+}
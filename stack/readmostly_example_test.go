@@ -0,0 +1,22 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleReadMostly() {
+	var s stack.ReadMostly[string]
+	s.Push("a")
+	s.Push("b")
+
+	// Peek, Empty, and Count can run in parallel with each other.
+	fmt.Println(s.Peek(), s.Count(), s.Empty())
+
+	fmt.Println(s.Pop(), s.Pop(), s.Empty())
+
+	// Output:
+	// b 2 false
+	// b a true
+}
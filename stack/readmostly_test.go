@@ -0,0 +1,210 @@
+package stack
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_ReadMostly_Push tests that ReadMostly's Push method adds a value to the top of the stack for
+// various stack configurations.
+func Test_ReadMostly_Push(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *ReadMostly[int]
+		require.NotPanics(t, func() { s.Push(1) })
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("zero stack", func(t *testing.T) {
+		var s ReadMostly[string]
+		s.Push("a")
+		s.Push("b")
+		require.Equal(t, 2, s.Count())
+		require.Equal(t, "b", s.Peek())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s ReadMostly[int]
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				s.Push(i)
+			}(i)
+			go func() {
+				defer wg.Done()
+				s.Peek()
+				s.Count()
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, 100, s.Count())
+	})
+}
+
+// Test_ReadMostly_CheckPop tests that ReadMostly's Pop and CheckPop methods remove and return the
+// value at the top of the stack for various stack configurations.
+func Test_ReadMostly_CheckPop(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *ReadMostly[int]
+		require.Zero(t, s.Pop())
+
+		top, ok := s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, top)
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s ReadMostly[int]
+		require.Zero(t, s.Pop())
+
+		top, ok := s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, top)
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s ReadMostly[int]
+		for i := 1; i <= 10; i++ {
+			s.Push(i)
+		}
+		for i := 10; i >= 1; i-- {
+			top, ok := s.CheckPop()
+			require.True(t, ok)
+			require.Equal(t, i, top)
+		}
+		require.True(t, s.Empty())
+	})
+
+	t.Run("releases references", func(t *testing.T) {
+		var s ReadMostly[*int]
+		one := 1
+		s.Push(&one)
+		s.Pop()
+		require.Nil(t, s.items[:1][0])
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s ReadMostly[int]
+		for i := 0; i < 100; i++ {
+			s.Push(i)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, ok := s.CheckPop()
+				require.True(t, ok)
+			}()
+		}
+		wg.Wait()
+
+		require.True(t, s.Empty())
+	})
+}
+
+// Test_ReadMostly_Peek tests that ReadMostly's Peek, Empty, Count, and Clear methods report and
+// reset the stack's contents, and that the read-only ones share the stack's lock.
+func Test_ReadMostly_Peek(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *ReadMostly[string]
+		require.Zero(t, s.Peek())
+		require.True(t, s.Empty())
+		require.Zero(t, s.Count())
+		require.NotPanics(t, func() { s.Clear() })
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s ReadMostly[string]
+		s.Push("a")
+		s.Push("b")
+		require.Equal(t, "b", s.Peek())
+		require.False(t, s.Empty())
+		require.Equal(t, 2, s.Count())
+
+		s.Clear()
+		require.Zero(t, s.Peek())
+		require.True(t, s.Empty())
+	})
+
+	t.Run("shared lock", func(t *testing.T) {
+		var s ReadMostly[int]
+		s.Push(1)
+
+		// With another reader holding the lock, reads must still go through.
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.Peek()
+			s.Empty()
+			s.Count()
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("readers blocked each other")
+		}
+	})
+}
+
+// Benchmark_ReadContention compares Stack and ReadMostly when many goroutines use them at once,
+// with reads alone, with one push and pop for every 100 reads, and with pushes and pops alone.
+func Benchmark_ReadContention(b *testing.B) {
+	type stack interface {
+		Push(int)
+		Pop() int
+		Peek() int
+		Count() int
+	}
+
+	stacks := []struct {
+		name string
+		new  func() stack
+	}{
+		{"Stack", func() stack { return new(Stack[int]) }},
+		{"ReadMostly", func() stack { return new(ReadMostly[int]) }},
+	}
+
+	workloads := []struct {
+		name       string
+		writeEvery int
+	}{
+		{"reads", 0},
+		{"mixed", 100},
+		{"writes", 1},
+	}
+
+	for _, w := range workloads {
+		for _, st := range stacks {
+			b.Run(st.name+"/"+w.name, func(b *testing.B) {
+				s := st.new()
+				s.Push(1)
+
+				b.RunParallel(func(pb *testing.PB) {
+					for i := 1; pb.Next(); i++ {
+						switch {
+						case w.writeEvery > 0 && i%w.writeEvery == 0:
+							s.Push(i)
+							s.Pop()
+						case i%2 == 0:
+							s.Peek()
+						default:
+							s.Count()
+						}
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package stack
+
+// Search looks for the value closest to the top of the stack for which pred returns true, and
+// returns its depth: 0 for the top of the stack, 1 for the value beneath it, and so on. pred is
+// called from the top of the stack down, while the stack is locked, and so must not use the stack.
+// If no value matches or pred is nil, this returns -1 and false.
+func (s *Stack[T]) Search(pred func(T) bool) (depth int, ok bool) {
+	if s == nil || pred == nil {
+		return -1, false
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := len(s.items) - 1; i >= 0; i-- {
+		if pred(s.items[i]) {
+			return len(s.items) - 1 - i, true
+		}
+	}
+
+	return -1, false
+}
+
+// Contains returns true if pred returns true for any value on the stack. It has the same
+// requirements for pred as Search.
+func (s *Stack[T]) Contains(pred func(T) bool) bool {
+	_, ok := s.Search(pred)
+	return ok
+}
+
+// RemoveFunc removes every value on the stack for which pred returns true and returns the number of
+// values removed. The remaining values keep their relative order. pred is called on every value,
+// from bottom to top, under a single lock acquisition, and so must not use the stack. If pred is
+// nil, nothing is removed.
+func (s *Stack[T]) RemoveFunc(pred func(T) bool) int {
+	if s == nil || pred == nil {
+		return 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var removed []T
+	kept := 0
+	for _, v := range s.items {
+		if !pred(v) {
+			s.items[kept] = v
+			kept++
+		} else if len(s.subscribers) > 0 {
+			removed = append(removed, v)
+		}
+	}
+
+	n := len(s.items) - kept
+	if n == 0 {
+		return 0
+	}
+
+	if len(removed) > 0 {
+		s.broadcast(Event[T]{
+			Kind:   EventRemoved,
+			Values: removed,
+		})
+	}
+
+	clear(s.items[kept:])
+	s.items = s.items[:kept]
+	s.stats.touched()
+	s.removed()
+
+	return n
+}
@@ -0,0 +1,45 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleStack_Search() {
+	var s stack.Stack[string]
+	s.PushMany("build", "test", "deploy")
+
+	depth, ok := s.Search(func(job string) bool { return job == "build" })
+
+	fmt.Println(depth, ok)
+
+	// Output:
+	// 2 true
+}
+
+func ExampleStack_Contains() {
+	var s stack.Stack[int]
+	s.PushMany(1, 2, 3)
+
+	fmt.Println(s.Contains(func(v int) bool { return v > 2 }))
+	fmt.Println(s.Contains(func(v int) bool { return v > 3 }))
+
+	// Output:
+	// true
+	// false
+}
+
+func ExampleStack_RemoveFunc() {
+	var s stack.Stack[string]
+	s.PushMany("job:1", "job:2", "job:3", "job:2")
+
+	n := s.RemoveFunc(func(job string) bool { return job == "job:2" })
+
+	fmt.Println(n)
+	fmt.Println(s.Drain())
+
+	// Output:
+	// 2
+	// [job:3 job:1]
+}
@@ -0,0 +1,160 @@
+package stack
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Stack_Search tests that Stack's Search and Contains methods find the matching value closest
+// to the top of the stack for various stack configurations.
+func Test_Stack_Search(t *testing.T) {
+	is := func(want string) func(string) bool {
+		return func(v string) bool { return v == want }
+	}
+
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[string]
+		depth, ok := s.Search(is("a"))
+		require.Equal(t, -1, depth)
+		require.False(t, ok)
+		require.False(t, s.Contains(is("a")))
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[string]
+		depth, ok := s.Search(is("a"))
+		require.Equal(t, -1, depth)
+		require.False(t, ok)
+		require.False(t, s.Contains(is("a")))
+	})
+
+	t.Run("nil predicate", func(t *testing.T) {
+		var s Stack[string]
+		s.Push("a")
+		depth, ok := s.Search(nil)
+		require.Equal(t, -1, depth)
+		require.False(t, ok)
+		require.False(t, s.Contains(nil))
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b", "a", "c")
+
+		depth, ok := s.Search(is("c"))
+		require.True(t, ok)
+		require.Equal(t, 0, depth)
+
+		depth, ok = s.Search(is("a"))
+		require.True(t, ok)
+		require.Equal(t, 1, depth)
+
+		depth, ok = s.Search(is("b"))
+		require.True(t, ok)
+		require.Equal(t, 2, depth)
+
+		depth, ok = s.Search(is("d"))
+		require.False(t, ok)
+		require.Equal(t, -1, depth)
+
+		require.True(t, s.Contains(is("b")))
+		require.False(t, s.Contains(is("d")))
+		require.Equal(t, 4, s.Count())
+	})
+}
+
+// Test_Stack_RemoveFunc tests that Stack's RemoveFunc method removes every matching value and keeps
+// the rest in order for various stack configurations.
+func Test_Stack_RemoveFunc(t *testing.T) {
+	odd := func(v int) bool { return v%2 == 1 }
+
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.Zero(t, s.RemoveFunc(odd))
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		require.Zero(t, s.RemoveFunc(odd))
+	})
+
+	t.Run("nil predicate", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+		require.Zero(t, s.RemoveFunc(nil))
+		require.Equal(t, 3, s.Count())
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(2, 4, 6)
+		require.Zero(t, s.RemoveFunc(odd))
+		require.Equal(t, []int{6, 4, 2}, s.Drain())
+	})
+
+	t.Run("some matches", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3, 4, 5, 6, 7)
+		require.Equal(t, 4, s.RemoveFunc(odd))
+		require.Equal(t, []int{6, 4, 2}, s.Drain())
+	})
+
+	t.Run("all match", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 3, 5)
+		require.Equal(t, 3, s.RemoveFunc(odd))
+		require.True(t, s.Empty())
+	})
+
+	t.Run("releases references", func(t *testing.T) {
+		var s Stack[*int]
+		one, two := 1, 2
+		s.PushMany(&one, &two)
+
+		require.Equal(t, 1, s.RemoveFunc(func(v *int) bool { return *v == 1 }))
+		require.Nil(t, s.items[:2][1])
+	})
+
+	t.Run("wakes blocked push", func(t *testing.T) {
+		s := NewBounded[int](2, OverflowBlock)
+		s.PushMany(1, 2)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.Push(3)
+		}()
+		awaitBlockedPush(t, s)
+
+		require.Equal(t, 1, s.RemoveFunc(odd))
+		<-done
+		require.Equal(t, []int{3, 2}, s.Drain())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				s.Push(i)
+			}(i)
+			go func() {
+				defer wg.Done()
+				s.RemoveFunc(odd)
+			}()
+		}
+		wg.Wait()
+		s.RemoveFunc(odd)
+
+		have := s.Drain()
+		require.Len(t, have, 50)
+		for _, v := range have {
+			require.Zero(t, v%2)
+		}
+	})
+}
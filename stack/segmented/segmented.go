@@ -0,0 +1,199 @@
+// Package segmented provides a stack that stores its values in fixed-size chunks instead of one
+// contiguous slice.
+package segmented
+
+import (
+	"sync"
+
+	"github.com/green-aloe/utilities/pool"
+)
+
+// DefaultChunkSize is the number of values in each chunk of a stack created without an explicit
+// chunk size.
+const DefaultChunkSize = 1024
+
+// maxSpareChunks is the number of empty chunks a stack keeps for reuse. Keeping one avoids
+// allocating a new chunk every time a stack that is hovering around a chunk boundary grows, and
+// any others are released so memory is returned as the stack shrinks.
+const maxSpareChunks = 1
+
+// A Stack is a first-in-last-out (FILO) data structure, like stack.Stack, that stores its values
+// in a linked list of fixed-size chunks. Growing the stack adds a chunk rather than reallocating
+// and copying every value, so pushes have predictable latency no matter how large the stack gets,
+// and shrinking the stack releases memory one chunk at a time. The zero value is an empty stack
+// with a chunk size of DefaultChunkSize and is ready to use. A stack is safe for concurrent use.
+type Stack[T any] struct {
+	// top is the chunk holding the top of the stack. Every chunk below it is full.
+	top       *chunk[T]
+	count     int
+	chunkSize int
+
+	// spares holds empty chunks for reuse.
+	spares pool.Pool[*chunk[T]]
+
+	mutex sync.Mutex
+}
+
+// A chunk is one segment of a stack. Its values are stored bottom to top, and its capacity is the
+// stack's chunk size.
+type chunk[T any] struct {
+	items []T
+	below *chunk[T]
+}
+
+// New returns a new stack that stores its values in chunks of chunkSize values. If chunkSize is
+// not positive, the stack uses DefaultChunkSize.
+func New[T any](chunkSize int) *Stack[T] {
+	return &Stack[T]{
+		chunkSize: chunkSize,
+	}
+}
+
+// Push adds a value to the top of the stack.
+func (s *Stack[T]) Push(v T) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.top == nil || len(s.top.items) == cap(s.top.items) {
+		c := s.spares.Get()
+		if c == nil {
+			c = &chunk[T]{items: make([]T, 0, s.size())}
+		}
+		c.below = s.top
+		s.top = c
+	}
+
+	s.top.items = append(s.top.items, v)
+	s.count++
+}
+
+// Pop removes and returns the value at the top of the stack. If the stack is empty, this returns
+// the zero value of the stack's type.
+func (s *Stack[T]) Pop() (t T) {
+	t, _ = s.CheckPop()
+	return t
+}
+
+// CheckPop returns the value at the top of the stack and a boolean indicating whether the stack is
+// empty. If the stack is empty, this returns the zero value of the stack's type and false.
+func (s *Stack[T]) CheckPop() (t T, ok bool) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.top == nil {
+		return
+	}
+
+	items := s.top.items
+	t, items[len(items)-1] = items[len(items)-1], t
+	s.top.items = items[:len(items)-1]
+	s.count--
+
+	if len(s.top.items) == 0 {
+		c := s.top
+		s.top = c.below
+		c.below = nil
+
+		// The chunk's values were zeroed as they were popped, so it's ready for reuse.
+		if s.spares.Count() < maxSpareChunks {
+			s.spares.Store(c)
+		}
+	}
+
+	return t, true
+}
+
+// Peek returns the value at the top of the stack without removing it. If the stack is empty, this
+// returns the zero value of the stack's type.
+func (s *Stack[T]) Peek() (t T) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.top == nil {
+		return
+	}
+
+	return s.top.items[len(s.top.items)-1]
+}
+
+// Empty returns true if the stack is empty.
+func (s *Stack[T]) Empty() bool {
+	return s.Count() == 0
+}
+
+// Count returns the number of elements in the stack.
+func (s *Stack[T]) Count() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.count
+}
+
+// Chunks returns the number of chunks the stack is using to store its values, not counting any
+// spare chunks kept for reuse.
+func (s *Stack[T]) Chunks() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var n int
+	for c := s.top; c != nil; c = c.below {
+		n++
+	}
+
+	return n
+}
+
+// Clear removes all elements from the stack and releases all of its memory.
+func (s *Stack[T]) Clear() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.top = nil
+	s.count = 0
+	s.spares.Clear()
+}
+
+// Trim releases any spare chunks the stack is keeping for reuse.
+func (s *Stack[T]) Trim() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.spares.Clear()
+}
+
+// size returns the number of values in each of the stack's chunks.
+func (s *Stack[T]) size() int {
+	if s.chunkSize <= 0 {
+		return DefaultChunkSize
+	}
+
+	return s.chunkSize
+}
@@ -0,0 +1,26 @@
+package segmented_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack/segmented"
+)
+
+func ExampleStack() {
+	s := segmented.New[int](4)
+	for i := 1; i <= 10; i++ {
+		s.Push(i)
+	}
+
+	fmt.Println(s.Count(), s.Chunks())
+
+	for i := 0; i < 7; i++ {
+		s.Pop()
+	}
+
+	fmt.Println(s.Count(), s.Chunks(), s.Peek())
+
+	// Output:
+	// 10 3
+	// 3 1 3
+}
@@ -0,0 +1,325 @@
+package segmented
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/green-aloe/utilities/stack"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_New tests that New returns a stack with the requested chunk size.
+func Test_New(t *testing.T) {
+	for _, test := range []struct {
+		chunkSize int
+		want      int
+	}{
+		{-1, DefaultChunkSize},
+		{0, DefaultChunkSize},
+		{1, 1},
+		{16, 16},
+	} {
+		s := New[int](test.chunkSize)
+		require.Equal(t, test.want, s.size())
+	}
+}
+
+// Test_Stack_Push tests that Stack's Push method adds a value to the top of the stack for various
+// stack configurations.
+func Test_Stack_Push(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NotPanics(t, func() { s.Push(1) })
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("zero stack", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		require.Equal(t, 1, s.Count())
+		require.Equal(t, 1, s.Chunks())
+	})
+
+	t.Run("across chunks", func(t *testing.T) {
+		s := New[int](4)
+		for i := 1; i <= 10; i++ {
+			s.Push(i)
+			require.Equal(t, i, s.Count())
+			require.Equal(t, (i+3)/4, s.Chunks())
+		}
+		for i := 10; i >= 1; i-- {
+			require.Equal(t, i, s.Pop())
+		}
+	})
+
+	t.Run("no copying", func(t *testing.T) {
+		s := New[int](4)
+		s.Push(1)
+		first := &s.top.items[0]
+
+		for i := 2; i <= 100; i++ {
+			s.Push(i)
+		}
+
+		// Growing the stack must not have moved the first value.
+		bottom := s.top
+		for bottom.below != nil {
+			bottom = bottom.below
+		}
+		require.Same(t, first, &bottom.items[0])
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		s := New[int](8)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.Push(i)
+			}(i)
+		}
+		wg.Wait()
+
+		require.Equal(t, 100, s.Count())
+		require.Equal(t, 13, s.Chunks())
+	})
+}
+
+// Test_Stack_CheckPop tests that Stack's Pop and CheckPop methods remove and return the value at
+// the top of the stack for various stack configurations.
+func Test_Stack_CheckPop(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[string]
+		require.Zero(t, s.Pop())
+
+		top, ok := s.CheckPop()
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[string]
+		require.Zero(t, s.Pop())
+
+		top, ok := s.CheckPop()
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+
+	t.Run("across chunks", func(t *testing.T) {
+		s := New[int](3)
+		for i := 0; i < 10; i++ {
+			s.Push(i)
+		}
+		for i := 9; i >= 0; i-- {
+			top, ok := s.CheckPop()
+			require.True(t, ok)
+			require.Equal(t, i, top)
+			require.Equal(t, (i+2)/3, s.Chunks())
+		}
+
+		top, ok := s.CheckPop()
+		require.Zero(t, top)
+		require.False(t, ok)
+	})
+
+	t.Run("releases references", func(t *testing.T) {
+		s := New[*int](4)
+		for i := 0; i < 4; i++ {
+			s.Push(new(int))
+		}
+		c := s.top
+		popN(s, 2)
+		require.Nil(t, c.items[:4][2])
+		require.Nil(t, c.items[:4][3])
+	})
+
+	t.Run("recycles chunks", func(t *testing.T) {
+		s := New[int](2)
+		for i := 0; i < 10; i++ {
+			s.Push(i)
+		}
+		for i := 0; i < 10; i++ {
+			s.Pop()
+		}
+		require.Zero(t, s.Chunks())
+		require.Equal(t, maxSpareChunks, s.spares.Count())
+
+		spare := s.spares.Get()
+		s.spares.Store(spare)
+		s.Push(1)
+		require.Same(t, spare, s.top)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		s := New[int](8)
+
+		var want []int
+		for i := 0; i < 100; i++ {
+			s.Push(i)
+			want = append(want, i)
+		}
+
+		ch := make(chan int, 100)
+		for i := 0; i < 100; i++ {
+			go func() {
+				top, ok := s.CheckPop()
+				require.True(t, ok)
+				ch <- top
+			}()
+		}
+
+		var have []int
+		for i := 0; i < 100; i++ {
+			have = append(have, <-ch)
+		}
+		sort.Ints(have)
+		require.Equal(t, want, have)
+		require.True(t, s.Empty())
+	})
+}
+
+// Test_Stack_Peek tests that Stack's Peek method returns the value at the top of the stack without
+// removing it for various stack configurations.
+func Test_Stack_Peek(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[rune]
+		require.Zero(t, s.Peek())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[rune]
+		require.Zero(t, s.Peek())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		s := New[rune](2)
+		for _, r := range "abc" {
+			s.Push(r)
+			require.Equal(t, r, s.Peek())
+		}
+		s.Pop()
+		require.Equal(t, 'b', s.Peek())
+		require.Equal(t, 2, s.Count())
+	})
+}
+
+// Test_Stack_Empty tests that Stack's Empty and Count methods report the size of the stack for
+// various stack configurations.
+func Test_Stack_Empty(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[bool]
+		require.True(t, s.Empty())
+		require.Zero(t, s.Count())
+		require.Zero(t, s.Chunks())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		s := New[bool](2)
+		s.Push(true)
+		require.False(t, s.Empty())
+		require.Equal(t, 1, s.Count())
+		s.Pop()
+		require.True(t, s.Empty())
+		require.Zero(t, s.Count())
+	})
+}
+
+// Test_Stack_Clear tests that Stack's Clear and Trim methods release the stack's memory for various
+// stack configurations.
+func Test_Stack_Clear(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NotPanics(t, func() { s.Clear() })
+		require.NotPanics(t, func() { s.Trim() })
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		s := New[int](2)
+		for i := 0; i < 10; i++ {
+			s.Push(i)
+		}
+		s.Pop()
+		s.Pop()
+		s.Clear()
+
+		require.True(t, s.Empty())
+		require.Zero(t, s.Chunks())
+		require.Zero(t, s.spares.Count())
+
+		s.Push(1)
+		require.Equal(t, 1, s.Pop())
+	})
+
+	t.Run("trim", func(t *testing.T) {
+		s := New[int](2)
+		for i := 0; i < 4; i++ {
+			s.Push(i)
+		}
+		s.Pop()
+		s.Pop()
+		require.Equal(t, 1, s.spares.Count())
+
+		s.Trim()
+		require.Zero(t, s.spares.Count())
+		require.Equal(t, 2, s.Count())
+	})
+}
+
+// Test_ConcurrentUse tests that various Stack methods can be used concurrently without panicking.
+func Test_ConcurrentUse(t *testing.T) {
+	s := New[int](4)
+
+	var wg sync.WaitGroup
+	wg.Add(10_000)
+	for i := 0; i < 10_000; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			switch i % 8 {
+			case 0, 1, 2:
+				s.Push(i)
+			case 3, 4:
+				s.Pop()
+			case 5:
+				s.Peek()
+			case 6:
+				s.Count()
+			case 7:
+				if i%64 == 7 {
+					s.Clear()
+				} else {
+					s.Trim()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// popN pops n values from s.
+func popN[T any](s *Stack[T], n int) {
+	for i := 0; i < n; i++ {
+		s.Pop()
+	}
+}
+
+// Benchmark_Push compares the cost of growing a segmented stack with growing a stack.Stack.
+func Benchmark_Push(b *testing.B) {
+	b.Run("stack.Stack", func(b *testing.B) {
+		var s stack.Stack[int]
+		for i := 0; i < b.N; i++ {
+			s.Push(i)
+		}
+	})
+
+	b.Run("segmented.Stack", func(b *testing.B) {
+		var s Stack[int]
+		for i := 0; i < b.N; i++ {
+			s.Push(i)
+		}
+	})
+}
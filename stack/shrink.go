@@ -0,0 +1,95 @@
+package stack
+
+// minShrinkCapacity is the smallest capacity that ShrinkQuarter will shrink a stack's storage to.
+// Below this, the memory saved isn't worth the copying.
+const minShrinkCapacity = 64
+
+// A ShrinkPolicy decides how much storage a stack keeps after values are removed from it. It is
+// called with the number of values left on the stack and the capacity of the stack's storage, and
+// returns the capacity the storage should have. If the returned capacity is smaller than the
+// current one (but not smaller than length), the stack moves its values into new storage of that
+// size and releases the old storage. Otherwise, the storage is left alone.
+type ShrinkPolicy func(length, capacity int) int
+
+// ShrinkQuarter is a ShrinkPolicy that shrinks a stack's storage to twice its length whenever the
+// stack falls below a quarter full. Because the stack must then lose half of its values again (or
+// double in size) before any more copying happens, the cost of shrinking is amortized O(1) per
+// operation. Storage is never shrunk below a small minimum capacity.
+func ShrinkQuarter(length, capacity int) int {
+	if capacity <= minShrinkCapacity || length >= capacity/4 {
+		return capacity
+	}
+
+	return max(2*length, minShrinkCapacity)
+}
+
+// SetShrinkPolicy sets the policy the stack uses to decide whether to release storage after values
+// are removed from it. A nil policy, which is the default, means storage is only released by Clear,
+// Drain, and Compact.
+func (s *Stack[T]) SetShrinkPolicy(policy ShrinkPolicy) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.shrinkPolicy = policy
+	s.shrink()
+}
+
+// Compact shrinks the stack's storage to exactly fit the values on it, releasing any unused memory.
+func (s *Stack[T]) Compact() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.resize(len(s.items))
+}
+
+// Cap returns the number of values the stack can hold before it needs to grow its storage. This is
+// unrelated to the capacity of a bounded stack.
+func (s *Stack[T]) Cap() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return cap(s.items)
+}
+
+// shrink applies the stack's shrink policy. The caller must hold the stack's lock.
+func (s *Stack[T]) shrink() {
+	if s.shrinkPolicy == nil {
+		return
+	}
+
+	size := s.shrinkPolicy(len(s.items), cap(s.items))
+	if size < len(s.items) || size >= cap(s.items) {
+		return
+	}
+
+	s.resize(size)
+}
+
+// resize moves the stack's values into new storage with the given capacity, which must be at least
+// the number of values. The caller must hold the stack's lock.
+func (s *Stack[T]) resize(size int) {
+	if size == cap(s.items) {
+		return
+	}
+
+	if size == 0 {
+		s.items = nil
+		return
+	}
+
+	items := make([]T, len(s.items), size)
+	copy(items, s.items)
+	s.items = items
+}
@@ -0,0 +1,38 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleStack_SetShrinkPolicy() {
+	var s stack.Stack[int]
+	s.SetShrinkPolicy(stack.ShrinkQuarter)
+
+	for i := 0; i < 100_000; i++ {
+		s.Push(i)
+	}
+	for i := 0; i < 99_990; i++ {
+		s.Pop()
+	}
+
+	fmt.Println(s.Count(), s.Cap())
+
+	// Output:
+	// 10 64
+}
+
+func ExampleStack_Compact() {
+	var s stack.Stack[int]
+	for i := 0; i < 1000; i++ {
+		s.Push(i)
+	}
+	s.PopN(995)
+
+	s.Compact()
+	fmt.Println(s.Count(), s.Cap())
+
+	// Output:
+	// 5 5
+}
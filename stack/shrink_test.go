@@ -0,0 +1,217 @@
+package stack
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_ShrinkQuarter tests that ShrinkQuarter only shrinks storage that is less than a quarter full.
+func Test_ShrinkQuarter(t *testing.T) {
+	tests := []struct {
+		length, capacity, want int
+	}{
+		{0, 0, 0},
+		{0, minShrinkCapacity, minShrinkCapacity},
+		{1, 1000, minShrinkCapacity},
+		{100, 1000, 200},
+		{249, 1000, 498},
+		{250, 1000, 1000},
+		{900, 1000, 1000},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d of %d", test.length, test.capacity), func(t *testing.T) {
+			require.Equal(t, test.want, ShrinkQuarter(test.length, test.capacity))
+		})
+	}
+}
+
+// Test_Stack_SetShrinkPolicy tests that a stack's shrink policy is applied as values are removed
+// for various stack configurations.
+func Test_Stack_SetShrinkPolicy(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NotPanics(t, func() { s.SetShrinkPolicy(ShrinkQuarter) })
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		var s Stack[int]
+		for i := 0; i < 10_000; i++ {
+			s.Push(i)
+		}
+		capacity := s.Cap()
+		s.PopN(9_999)
+		require.Equal(t, capacity, s.Cap())
+	})
+
+	t.Run("shrink quarter", func(t *testing.T) {
+		var s Stack[int]
+		s.SetShrinkPolicy(ShrinkQuarter)
+		for i := 0; i < 10_000; i++ {
+			s.Push(i)
+		}
+		peak := s.Cap()
+
+		for i := 9_999; i >= 0; i-- {
+			require.Equal(t, i, s.Pop())
+			require.GreaterOrEqual(t, s.Cap(), s.Count())
+			if s.Cap() > minShrinkCapacity {
+				require.GreaterOrEqual(t, s.Count(), s.Cap()/4)
+			}
+		}
+		require.Less(t, s.Cap(), peak)
+		require.LessOrEqual(t, s.Cap(), minShrinkCapacity)
+	})
+
+	t.Run("applied immediately", func(t *testing.T) {
+		var s Stack[int]
+		for i := 0; i < 10_000; i++ {
+			s.Push(i)
+		}
+		s.PopN(9_990)
+
+		s.SetShrinkPolicy(ShrinkQuarter)
+		require.Equal(t, minShrinkCapacity, s.Cap())
+		require.Equal(t, 10, s.Count())
+		require.Equal(t, 9, s.Pop())
+	})
+
+	t.Run("custom policy", func(t *testing.T) {
+		var s Stack[int]
+		s.SetShrinkPolicy(func(length, capacity int) int { return length })
+		s.PushMany(1, 2, 3, 4, 5)
+
+		s.Pop()
+		require.Equal(t, 4, s.Cap())
+		s.PopN(2)
+		require.Equal(t, 2, s.Cap())
+		require.Equal(t, []int{2, 1}, s.Drain())
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		var s Stack[int]
+		s.SetShrinkPolicy(func(length, capacity int) int { return -1 })
+		s.PushMany(1, 2, 3, 4, 5)
+		capacity := s.Cap()
+
+		s.PopN(4)
+		require.Equal(t, capacity, s.Cap())
+		require.Equal(t, 1, s.Pop())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+		s.SetShrinkPolicy(ShrinkQuarter)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					s.Push(j)
+				}
+				for j := 0; j < 100; j++ {
+					s.Pop()
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.True(t, s.Empty())
+		require.LessOrEqual(t, s.Cap(), minShrinkCapacity)
+	})
+}
+
+// Test_Stack_Compact tests that Stack's Compact method shrinks the stack's storage to fit its
+// values for various stack configurations.
+func Test_Stack_Compact(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NotPanics(t, func() { s.Compact() })
+		require.Zero(t, s.Cap())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		s.Compact()
+		require.Zero(t, s.Cap())
+
+		s.PushMany(1, 2, 3)
+		s.PopN(3)
+		require.NotZero(t, s.Cap())
+		s.Compact()
+		require.Zero(t, s.Cap())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[int]
+		for i := 0; i < 1000; i++ {
+			s.Push(i)
+		}
+		s.PopN(900)
+		require.Greater(t, s.Cap(), 100)
+
+		s.Compact()
+		require.Equal(t, 100, s.Cap())
+		require.Equal(t, 100, s.Count())
+		require.Equal(t, 99, s.Peek())
+	})
+}
+
+// Test_Stack_Cap tests that Stack's Cap method reports the capacity of the stack's storage for
+// various stack configurations.
+func Test_Stack_Cap(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.Zero(t, s.Cap())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		require.Zero(t, s.Cap())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[int]
+		for i := 0; i < 100; i++ {
+			s.Push(i)
+			require.GreaterOrEqual(t, s.Cap(), s.Count())
+		}
+
+		s.Clear()
+		require.Zero(t, s.Cap())
+	})
+}
+
+// Benchmark_Shrink measures the cost per operation of repeatedly filling and emptying a stack, with
+// and without a shrink policy. With ShrinkQuarter, the cost per operation should stay roughly flat
+// as the burst size grows.
+func Benchmark_Shrink(b *testing.B) {
+	for _, policy := range []struct {
+		name   string
+		policy ShrinkPolicy
+	}{
+		{"none", nil},
+		{"quarter", ShrinkQuarter},
+	} {
+		for _, burst := range []int{1_000, 100_000, 1_000_000} {
+			b.Run(fmt.Sprintf("%s/%d", policy.name, burst), func(b *testing.B) {
+				var s Stack[int]
+				s.SetShrinkPolicy(policy.policy)
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if (i/burst)%2 == 0 {
+						s.Push(i)
+					} else {
+						s.Pop()
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,323 @@
+// Package spill provides a stack that keeps its top values in memory and spills older values to
+// temporary files, so it can hold more values than fit in memory.
+package spill
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// DefaultSegmentSize is the number of values in each segment of a stack created without an
+// explicit segment size.
+const DefaultSegmentSize = 4096
+
+// A Codec encodes segments of a stack's values to its temporary files and decodes them back.
+type Codec[T any] interface {
+	// Encode writes values to w, ordered from bottom to top.
+	Encode(w io.Writer, values []T) error
+	// Decode reads values written by Encode from r, in the same order.
+	Decode(r io.Reader) ([]T, error)
+}
+
+// GobCodec is a Codec that uses encoding/gob, so the same restrictions on types apply.
+type GobCodec[T any] struct{}
+
+// Encode implements Codec.
+func (GobCodec[T]) Encode(w io.Writer, values []T) error {
+	return gob.NewEncoder(w).Encode(values)
+}
+
+// Decode implements Codec.
+func (GobCodec[T]) Decode(r io.Reader) ([]T, error) {
+	var values []T
+	err := gob.NewDecoder(r).Decode(&values)
+	return values, err
+}
+
+// A Stack is a first-in-last-out (FILO) data structure, like stack.Stack, that keeps at most two
+// segments of its top values in memory. When a push would grow the stack past that, the bottom
+// segment in memory is encoded to a temporary file and released. When pops empty the memory, the
+// most recently spilled segment is read back in and its file is removed. Holding two segments
+// means a stack hovering around a segment boundary doesn't write and read a file on every push and
+// pop.
+//
+// The zero value is an empty stack that spills segments of DefaultSegmentSize values to the
+// default directory for temporary files using GobCodec, and is ready to use. A stack is safe for
+// concurrent use. Call Clear when a stack is no longer needed to remove its files.
+type Stack[T any] struct {
+	// items holds the values in memory, from bottom to top. They sit above every spilled value.
+	items []T
+
+	// segments holds the spilled segments, from bottom to top.
+	segments []segment
+	spilled  int
+
+	dir         string
+	segmentSize int
+	codec       Codec[T]
+
+	// err is the first error encountered while reading a segment back in.
+	err error
+
+	mutex sync.Mutex
+}
+
+// A segment is a group of values that has been spilled to a file.
+type segment struct {
+	name  string
+	count int
+}
+
+// New returns a new stack that spills segments of segmentSize values to temporary files in dir,
+// encoded with codec. If dir is empty, the stack uses the default directory for temporary files
+// (see os.TempDir). If segmentSize is not positive, the stack uses DefaultSegmentSize. If codec is
+// nil, the stack uses GobCodec.
+func New[T any](dir string, segmentSize int, codec Codec[T]) *Stack[T] {
+	return &Stack[T]{
+		dir:         dir,
+		segmentSize: segmentSize,
+		codec:       codec,
+	}
+}
+
+// Push adds a value to the top of the stack. If the stack has to spill a segment to make room for
+// the value and can't, the value is not pushed and this returns the error. If the stack is nil,
+// the value is discarded.
+func (s *Stack[T]) Push(v T) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.items) >= 2*s.size() {
+		if err := s.spill(); err != nil {
+			return err
+		}
+	}
+
+	s.items = append(s.items, v)
+
+	return nil
+}
+
+// Pop removes and returns the value at the top of the stack. If the stack is empty, or a spilled
+// segment can't be read back in, this returns the zero value of the stack's type.
+func (s *Stack[T]) Pop() (t T) {
+	t, _ = s.CheckPop()
+	return t
+}
+
+// CheckPop returns the value at the top of the stack and a boolean indicating whether the stack is
+// empty. If the stack is empty, this returns the zero value of the stack's type and false. If a
+// spilled segment can't be read back in, this also returns the zero value and false, and the error
+// is available from Err; the segment stays on disk and the next pop tries again.
+func (s *Stack[T]) CheckPop() (t T, ok bool) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.fill() {
+		return
+	}
+
+	t, s.items[len(s.items)-1] = s.items[len(s.items)-1], t
+	s.items = s.items[:len(s.items)-1]
+
+	return t, true
+}
+
+// Peek returns the value at the top of the stack without removing it. If the stack is empty, or a
+// spilled segment can't be read back in, this returns the zero value of the stack's type.
+func (s *Stack[T]) Peek() (t T) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.fill() {
+		return
+	}
+
+	return s.items[len(s.items)-1]
+}
+
+// Empty returns true if the stack is empty.
+func (s *Stack[T]) Empty() bool {
+	return s.Count() == 0
+}
+
+// Count returns the number of elements in the stack, including any that have been spilled.
+func (s *Stack[T]) Count() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.items) + s.spilled
+}
+
+// Spilled returns the number of elements in the stack that are stored in files rather than in
+// memory.
+func (s *Stack[T]) Spilled() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.spilled
+}
+
+// Err returns the first error encountered while reading a spilled segment back in, or nil if there
+// hasn't been one since the stack was created or last cleared.
+func (s *Stack[T]) Err() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.err
+}
+
+// Clear removes all elements from the stack, removes all of its files, and resets Err. If any file
+// can't be removed, this returns the errors joined together, but the stack is empty regardless.
+func (s *Stack[T]) Clear() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var errs []error
+	for _, seg := range s.segments {
+		if err := os.Remove(seg.name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	s.items = nil
+	s.segments = nil
+	s.spilled = 0
+	s.err = nil
+
+	return errors.Join(errs...)
+}
+
+// spill writes the bottom segment of the values in memory to a new file and releases it. The
+// caller must hold the stack's lock.
+func (s *Stack[T]) spill() error {
+	n := min(s.size(), len(s.items))
+
+	f, err := os.CreateTemp(s.dir, "spill-*")
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	err = s.coder().Encode(w, s.items[:n])
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	s.segments = append(s.segments, segment{name: f.Name(), count: n})
+	s.spilled += n
+
+	// Moving the remaining values down keeps the backing array from growing without bound.
+	m := copy(s.items, s.items[n:])
+	clear(s.items[m:])
+	s.items = s.items[:m]
+
+	return nil
+}
+
+// fill makes sure there is a value in memory by reading the most recently spilled segment back in
+// if necessary, and reports whether there is one. The caller must hold the stack's lock.
+func (s *Stack[T]) fill() bool {
+	if len(s.items) > 0 {
+		return true
+	}
+	if len(s.segments) == 0 {
+		return false
+	}
+
+	seg := s.segments[len(s.segments)-1]
+	items, err := s.load(seg)
+	if err != nil {
+		if s.err == nil {
+			s.err = err
+		}
+		return false
+	}
+
+	s.segments[len(s.segments)-1] = segment{}
+	s.segments = s.segments[:len(s.segments)-1]
+	s.spilled -= seg.count
+	s.items = items
+
+	// The values are in memory now, so a leftover file only wastes space.
+	os.Remove(seg.name)
+
+	return true
+}
+
+// load reads a spilled segment's values from its file.
+func (s *Stack[T]) load(seg segment) ([]T, error) {
+	f, err := os.Open(seg.name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	items, err := s.coder().Decode(bufio.NewReader(f))
+	if err != nil {
+		return nil, err
+	}
+	if len(items) != seg.count {
+		return nil, fmt.Errorf("spill: segment %s has %d values, want %d", seg.name, len(items), seg.count)
+	}
+
+	return items, nil
+}
+
+// size returns the number of values in each of the stack's segments.
+func (s *Stack[T]) size() int {
+	if s.segmentSize <= 0 {
+		return DefaultSegmentSize
+	}
+
+	return s.segmentSize
+}
+
+// coder returns the codec the stack uses for its files.
+func (s *Stack[T]) coder() Codec[T] {
+	if s.codec == nil {
+		return GobCodec[T]{}
+	}
+
+	return s.codec
+}
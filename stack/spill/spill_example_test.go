@@ -0,0 +1,37 @@
+package spill_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/green-aloe/utilities/stack/spill"
+)
+
+func ExampleStack() {
+	dir, err := os.MkdirTemp("", "example")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := spill.New[int](dir, 4, nil)
+	defer s.Clear()
+
+	for i := 1; i <= 10; i++ {
+		if err := s.Push(i); err != nil {
+			panic(err)
+		}
+	}
+
+	fmt.Println(s.Count(), s.Spilled())
+
+	for i := 0; i < 7; i++ {
+		s.Pop()
+	}
+
+	fmt.Println(s.Count(), s.Spilled(), s.Peek(), s.Err())
+
+	// Output:
+	// 10 4
+	// 3 0 3 <nil>
+}
@@ -0,0 +1,371 @@
+package spill
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// files returns the number of files in dir.
+func files(t *testing.T, dir string) int {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	return len(entries)
+}
+
+// jsonCodec is a Codec that uses encoding/json.
+type jsonCodec[T any] struct{}
+
+func (jsonCodec[T]) Encode(w io.Writer, values []T) error {
+	return json.NewEncoder(w).Encode(values)
+}
+
+func (jsonCodec[T]) Decode(r io.Reader) ([]T, error) {
+	var values []T
+	err := json.NewDecoder(r).Decode(&values)
+	return values, err
+}
+
+// faultyCodec is a Codec that fails to encode or decode on demand.
+type faultyCodec[T any] struct {
+	GobCodec[T]
+	failEncode bool
+	failDecode bool
+}
+
+var errFaulty = errors.New("faulty codec")
+
+func (c *faultyCodec[T]) Encode(w io.Writer, values []T) error {
+	if c.failEncode {
+		return errFaulty
+	}
+	return c.GobCodec.Encode(w, values)
+}
+
+func (c *faultyCodec[T]) Decode(r io.Reader) ([]T, error) {
+	if c.failDecode {
+		return nil, errFaulty
+	}
+	return c.GobCodec.Decode(r)
+}
+
+// Test_New tests that New returns a stack with the requested configuration.
+func Test_New(t *testing.T) {
+	for _, test := range []struct {
+		segmentSize int
+		want        int
+	}{
+		{-1, DefaultSegmentSize},
+		{0, DefaultSegmentSize},
+		{1, 1},
+		{16, 16},
+	} {
+		s := New[int]("", test.segmentSize, nil)
+		require.Equal(t, test.want, s.size())
+		require.Equal(t, GobCodec[int]{}, s.coder())
+	}
+
+	s := New[int]("", 0, jsonCodec[int]{})
+	require.Equal(t, jsonCodec[int]{}, s.coder())
+}
+
+// Test_Stack_Push tests that Stack's Push method adds a value to the top of the stack, spilling
+// segments as needed, for various stack configurations.
+func Test_Stack_Push(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NoError(t, s.Push(1))
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("zero stack", func(t *testing.T) {
+		var s Stack[int]
+		require.NoError(t, s.Push(1))
+		require.Equal(t, 1, s.Count())
+		require.Zero(t, s.Spilled())
+	})
+
+	t.Run("spilling", func(t *testing.T) {
+		dir := t.TempDir()
+		s := New[int](dir, 4, nil)
+		defer s.Clear()
+
+		for i := 1; i <= 8; i++ {
+			require.NoError(t, s.Push(i))
+		}
+		require.Zero(t, s.Spilled())
+		require.Zero(t, files(t, dir))
+
+		for i := 9; i <= 20; i++ {
+			require.NoError(t, s.Push(i))
+			require.Equal(t, i, s.Count())
+			require.LessOrEqual(t, len(s.items), 8)
+		}
+		require.Equal(t, 12, s.Spilled())
+		require.Equal(t, 3, files(t, dir))
+		require.Equal(t, 20, s.Peek())
+	})
+
+	t.Run("encode error", func(t *testing.T) {
+		dir := t.TempDir()
+		codec := &faultyCodec[int]{failEncode: true}
+		s := New[int](dir, 2, codec)
+
+		for i := 1; i <= 4; i++ {
+			require.NoError(t, s.Push(i))
+		}
+		require.ErrorIs(t, s.Push(5), errFaulty)
+		require.Equal(t, 4, s.Count())
+		require.Zero(t, files(t, dir))
+
+		codec.failEncode = false
+		require.NoError(t, s.Push(5))
+		require.Equal(t, 5, s.Count())
+		require.Equal(t, 1, files(t, dir))
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		s := New[int](t.TempDir()+"/missing", 1, nil)
+		require.NoError(t, s.Push(1))
+		require.NoError(t, s.Push(2))
+		require.Error(t, s.Push(3))
+		require.Equal(t, 2, s.Count())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		dir := t.TempDir()
+		s := New[int](dir, 8, nil)
+		defer s.Clear()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					require.NoError(t, s.Push(i*10+j))
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		require.Equal(t, 1000, s.Count())
+
+		var values []int
+		for !s.Empty() {
+			values = append(values, s.Pop())
+		}
+		sort.Ints(values)
+		for i, v := range values {
+			require.Equal(t, i, v)
+		}
+		require.Zero(t, files(t, dir))
+	})
+}
+
+// Test_Stack_Pop tests that Stack's Pop and CheckPop methods remove values from the top of the
+// stack, reading spilled segments back in as needed, for various stack configurations.
+func Test_Stack_Pop(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.Zero(t, s.Pop())
+
+		v, ok := s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, v)
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		require.Zero(t, s.Pop())
+
+		v, ok := s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, v)
+	})
+
+	t.Run("reloading", func(t *testing.T) {
+		dir := t.TempDir()
+		s := New[string](dir, 3, jsonCodec[string]{})
+
+		words := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m"}
+		for _, w := range words {
+			require.NoError(t, s.Push(w))
+		}
+		require.Equal(t, 3, files(t, dir))
+
+		for i := len(words) - 1; i >= 0; i-- {
+			v, ok := s.CheckPop()
+			require.True(t, ok)
+			require.Equal(t, words[i], v)
+			require.Equal(t, i, s.Count())
+		}
+		require.Zero(t, files(t, dir))
+		require.Zero(t, s.Spilled())
+
+		v, ok := s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, v)
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("interleaved", func(t *testing.T) {
+		dir := t.TempDir()
+		s := New[int](dir, 2, nil)
+		defer s.Clear()
+
+		var want []int
+		for i := 0; i < 200; i++ {
+			if i%3 == 2 {
+				require.Equal(t, want[len(want)-1], s.Pop())
+				want = want[:len(want)-1]
+			} else {
+				require.NoError(t, s.Push(i))
+				want = append(want, i)
+			}
+			require.Equal(t, len(want), s.Count())
+		}
+
+		for len(want) > 0 {
+			require.Equal(t, want[len(want)-1], s.Pop())
+			want = want[:len(want)-1]
+		}
+		require.True(t, s.Empty())
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		dir := t.TempDir()
+		codec := &faultyCodec[int]{}
+		s := New[int](dir, 2, codec)
+		defer s.Clear()
+
+		for i := 1; i <= 5; i++ {
+			require.NoError(t, s.Push(i))
+		}
+		require.Equal(t, 5, s.Pop())
+		require.Equal(t, 4, s.Pop())
+		require.Equal(t, 3, s.Pop())
+
+		codec.failDecode = true
+		v, ok := s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, v)
+		require.Zero(t, s.Peek())
+		require.ErrorIs(t, s.Err(), errFaulty)
+		require.Equal(t, 2, s.Count())
+		require.Equal(t, 1, files(t, dir))
+
+		// The segment is still on disk, so a later pop can succeed, but the error sticks.
+		codec.failDecode = false
+		require.Equal(t, 2, s.Pop())
+		require.Equal(t, 1, s.Pop())
+		require.ErrorIs(t, s.Err(), errFaulty)
+		require.Zero(t, files(t, dir))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		dir := t.TempDir()
+		s := New[int](dir, 1, nil)
+		for i := 1; i <= 3; i++ {
+			require.NoError(t, s.Push(i))
+		}
+		require.NoError(t, os.Remove(s.segments[0].name))
+
+		require.Equal(t, 3, s.Pop())
+		require.Equal(t, 2, s.Pop())
+		_, ok := s.CheckPop()
+		require.False(t, ok)
+		require.ErrorIs(t, s.Err(), os.ErrNotExist)
+	})
+}
+
+// Test_Stack_Peek tests that Stack's Peek method returns the value at the top of the stack,
+// reading a spilled segment back in if needed.
+func Test_Stack_Peek(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.Zero(t, s.Peek())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		require.Zero(t, s.Peek())
+	})
+
+	t.Run("spilled stack", func(t *testing.T) {
+		dir := t.TempDir()
+		s := New[int](dir, 1, nil)
+		for i := 1; i <= 3; i++ {
+			require.NoError(t, s.Push(i))
+		}
+		require.Equal(t, 3, s.Pop())
+		require.Equal(t, 2, s.Pop())
+		require.Equal(t, 1, s.Spilled())
+
+		require.Equal(t, 1, s.Peek())
+		require.Equal(t, 1, s.Count())
+		require.Zero(t, s.Spilled())
+		require.Zero(t, files(t, dir))
+	})
+}
+
+// Test_Stack_Clear tests that Stack's Clear method removes all values and files from the stack.
+func Test_Stack_Clear(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NoError(t, s.Clear())
+	})
+
+	t.Run("spilled stack", func(t *testing.T) {
+		dir := t.TempDir()
+		codec := &faultyCodec[int]{}
+		s := New[int](dir, 2, codec)
+		for i := 1; i <= 10; i++ {
+			require.NoError(t, s.Push(i))
+		}
+		s.items = nil
+		codec.failDecode = true
+		s.Pop()
+		require.Error(t, s.Err())
+		require.NotZero(t, files(t, dir))
+
+		require.NoError(t, s.Clear())
+		require.Zero(t, files(t, dir))
+		require.True(t, s.Empty())
+		require.Zero(t, s.Spilled())
+		require.NoError(t, s.Err())
+
+		codec.failDecode = false
+		require.NoError(t, s.Push(1))
+		require.Equal(t, 1, s.Pop())
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		dir := t.TempDir()
+		s := New[int](dir, 1, nil)
+		for i := 1; i <= 3; i++ {
+			require.NoError(t, s.Push(i))
+		}
+		require.NoError(t, os.Remove(s.segments[0].name))
+		require.NoError(t, s.Clear())
+	})
+}
+
+func Benchmark_Stack(b *testing.B) {
+	s := New[int](b.TempDir(), 1024, nil)
+	defer s.Clear()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Push(i)
+	}
+	for i := 0; i < b.N; i++ {
+		s.Pop()
+	}
+}
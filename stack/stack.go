@@ -1,6 +1,45 @@
 package stack
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned when a value is pushed onto a closed stack, or when a closed stack has no
+// more values to pop.
+var ErrClosed = errors.New("stack: closed")
+
+// ErrFull is returned when a value is pushed onto a bounded stack that is at capacity and whose
+// overflow policy doesn't make room for it.
+var ErrFull = errors.New("stack: full")
+
+// An OverflowPolicy determines what a bounded stack does when a value is pushed onto it while it is
+// at capacity.
+type OverflowPolicy int
+
+const (
+	// OverflowReject refuses the new value. Methods that report errors, such as TryPush, return
+	// ErrFull.
+	OverflowReject OverflowPolicy = iota
+	// OverflowBlock waits until another goroutine pops a value and makes room.
+	OverflowBlock
+	// OverflowEvict discards the value at the bottom of the stack to make room.
+	OverflowEvict
+)
+
+// A PushResult describes what happened when a value was pushed onto a stack.
+type PushResult int
+
+const (
+	// Stored means the value was added to the stack without displacing any other value.
+	Stored PushResult = iota
+	// Evicted means the value was added to the stack and the bottom-most value was discarded to
+	// make room for it.
+	Evicted
+	// Rejected means the value was not added to the stack.
+	Rejected
+)
 
 // A stack is a first-in-last-out (FILO) data structure: the last element pushed onto the stack is
 // the first one popped from it. The zero value is an empty stack and ready to use. A stack is safe
@@ -8,18 +47,221 @@ import "sync"
 type Stack[T any] struct {
 	items []T
 	mutex sync.Mutex
+
+	// capacity is the maximum number of items the stack holds, or 0 if the stack is unbounded.
+	capacity int
+	policy   OverflowPolicy
+
+	// waiters holds one channel for every goroutine parked in PopWait, oldest first. A waiter is
+	// only ever registered while items is empty, so a push always hands its value to the oldest
+	// waiter instead of storing it.
+	waiters []chan T
+
+	// space is closed (and reset) whenever an item is removed from the stack, waking any pushes
+	// that are blocked waiting for room.
+	space chan struct{}
+
+	// shrinkPolicy decides whether to release storage after items are removed, or is nil to never
+	// release it.
+	shrinkPolicy ShrinkPolicy
+
+	subscribers []*Subscription[T]
+
+	// stats records operation metrics, or is nil if stats are disabled.
+	stats *stats
+
+	closed bool
 }
 
-// Push adds a value to the top of the stack.
+// NewBounded returns a new stack that holds at most capacity values. When a value is pushed onto
+// the stack while it is full, policy determines what happens. If capacity is not positive, the
+// stack is unbounded.
+func NewBounded[T any](capacity int, policy OverflowPolicy) *Stack[T] {
+	return &Stack[T]{
+		capacity: max(capacity, 0),
+		policy:   policy,
+	}
+}
+
+// Push adds a value to the top of the stack. If any goroutines are blocked in PopWait, the value is
+// handed directly to the one that has been waiting the longest instead.
+//
+// If the stack is bounded and full, the stack's overflow policy determines what happens: the value
+// is discarded, Push blocks until there is room, or the bottom-most value is evicted. If the stack
+// is closed, the value is discarded. Use TryPush or PushContext to learn whether the value was
+// stored.
 func (s *Stack[T]) Push(v T) {
+	s.push(context.Background(), []T{v}, true)
+}
+
+// TryPush adds a value to the top of the stack, like Push, without ever waiting for room. If the
+// stack is bounded and full, the value is rejected with ErrFull, unless the stack's overflow policy
+// is OverflowEvict, in which case the bottom-most value is evicted. If the stack is closed, the
+// value is rejected with ErrClosed.
+func (s *Stack[T]) TryPush(v T) error {
+	_, err := s.push(context.Background(), []T{v}, false)
+	return err
+}
+
+// PushContext adds a value to the top of the stack, like Push, and reports what happened. If the
+// stack is bounded, full, and configured with OverflowBlock, this waits until there is room or ctx
+// is done, in which case the value is rejected and this returns ctx's error. If the stack is closed,
+// or is closed while this is waiting, the value is rejected and this returns ErrClosed. If the
+// stack is nil, the value is discarded and this returns Rejected.
+func (s *Stack[T]) PushContext(ctx context.Context, v T) (PushResult, error) {
+	return s.push(ctx, []T{v}, true)
+}
+
+// push adds values to the top of the stack in order, as one operation. If the stack is bounded and
+// the values don't fit, the stack's overflow policy applies to the whole batch: either every value
+// is rejected, the push waits until all of them fit, or enough values are evicted from the bottom
+// to make room. If wait is false, a push that would have to wait is rejected with ErrFull instead.
+func (s *Stack[T]) push(ctx context.Context, vs []T, wait bool) (PushResult, error) {
 	if s == nil {
-		return
+		return Rejected, nil
 	}
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	for {
+		s.mutex.Lock()
+
+		if s.closed {
+			s.mutex.Unlock()
+			return Rejected, ErrClosed
+		}
+
+		// Values handed to waiters don't take up any room.
+		stored := len(vs) - min(len(s.waiters), len(vs))
+		if s.capacity == 0 || len(s.items)+stored <= s.capacity {
+			s.storeAll(vs)
+			s.mutex.Unlock()
+
+			return Stored, nil
+		}
+
+		switch s.policy {
+		case OverflowEvict:
+			s.storeAll(vs)
+			s.evict()
+			s.mutex.Unlock()
+
+			return Evicted, nil
+
+		case OverflowBlock:
+			if stored > s.capacity || !wait {
+				// These values will never fit, no matter how long we wait, or the caller won't wait.
+				s.mutex.Unlock()
+				return Rejected, ErrFull
+			}
+
+			if s.space == nil {
+				s.space = make(chan struct{})
+			}
+			space := s.space
+			s.mutex.Unlock()
+
+			select {
+			case <-space:
+			case <-ctx.Done():
+				return Rejected, ctx.Err()
+			}
+
+		default:
+			s.mutex.Unlock()
+			return Rejected, ErrFull
+		}
+	}
+}
+
+// store adds a value to the top of the stack, or hands it to the oldest waiter if there is one. The
+// caller must hold the stack's lock and have already made room for the value.
+func (s *Stack[T]) store(v T) {
+	if len(s.waiters) > 0 {
+		ch := s.waiters[0]
+		s.waiters[0] = nil
+		s.waiters = s.waiters[1:]
+
+		// The channel is buffered and receives at most one value, so this never blocks.
+		ch <- v
+		s.notify(EventPushed, v)
+		s.notify(EventPopped, v)
+		s.stats.pushed(1, s.depth())
+		s.stats.popped(1)
+
+		return
+	}
 
 	s.items = append(s.items, v)
+	s.notify(EventPushed, v)
+	s.stats.pushed(1, s.depth())
+}
+
+// storeAll adds values to the top of the stack in order, handing the first ones to any waiters, as
+// if they were stored one at a time. The caller must hold the stack's lock and have already made
+// room for the values.
+func (s *Stack[T]) storeAll(vs []T) {
+	for len(vs) > 0 && len(s.waiters) > 0 {
+		s.store(vs[0])
+		vs = vs[1:]
+	}
+
+	if len(vs) == 0 {
+		return
+	}
+
+	s.items = append(s.items, vs...)
+	s.notify(EventPushed, vs...)
+	s.stats.pushed(len(vs), s.depth())
+}
+
+// evict discards values from the bottom of the stack until it is within its capacity. The caller
+// must hold the stack's lock.
+func (s *Stack[T]) evict() {
+	if s.capacity == 0 || len(s.items) <= s.capacity {
+		return
+	}
+
+	excess := len(s.items) - s.capacity
+	s.notify(EventEvicted, s.items[:excess]...)
+	clear(s.items[:excess])
+	s.items = s.items[excess:]
+	s.stats.touched()
+}
+
+// depth returns the number of values the stack holds once any excess over its capacity has been
+// evicted. The caller must hold the stack's lock.
+func (s *Stack[T]) depth() int {
+	if s.capacity > 0 {
+		return min(len(s.items), s.capacity)
+	}
+
+	return len(s.items)
+}
+
+// pop removes and returns the value at the top of the stack and reports whether there was one. The
+// caller must hold the stack's lock.
+func (s *Stack[T]) pop() (t T, ok bool) {
+	if len(s.items) == 0 {
+		return
+	}
+
+	t, s.items[len(s.items)-1] = s.items[len(s.items)-1], t
+	s.items = s.items[:len(s.items)-1]
+	s.notify(EventPopped, t)
+	s.stats.popped(1)
+	s.removed()
+
+	return t, true
+}
+
+// removed wakes any pushes that are blocked waiting for room and applies the stack's shrink policy.
+// The caller must hold the stack's lock and must call this after removing items from the stack.
+func (s *Stack[T]) removed() {
+	if s.space != nil {
+		close(s.space)
+		s.space = nil
+	}
+
+	s.shrink()
 }
 
 // Pop removes and returns the value at the top of the stack. If the stack is empty, this returns
@@ -32,13 +274,11 @@ func (s *Stack[T]) Pop() (t T) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if len(s.items) == 0 {
-		return
+	t, ok := s.pop()
+	if !ok {
+		s.stats.emptyPop()
 	}
 
-	t, s.items[len(s.items)-1] = s.items[len(s.items)-1], t
-	s.items = s.items[:len(s.items)-1]
-
 	return t
 }
 
@@ -52,14 +292,85 @@ func (s *Stack[T]) CheckPop() (t T, ok bool) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if len(s.items) == 0 {
-		return
+	if t, ok = s.pop(); !ok {
+		s.stats.emptyPop()
 	}
 
-	t, s.items[len(s.items)-1] = s.items[len(s.items)-1], t
-	s.items = s.items[:len(s.items)-1]
+	return t, ok
+}
 
-	return t, true
+// PopWait removes and returns the value at the top of the stack. If the stack is empty, this blocks
+// until a value is pushed or ctx is done, whichever happens first. Each pushed value wakes exactly
+// one waiter, and waiters are served in the order they started waiting. If ctx is done before a
+// value arrives, this returns the zero value of the stack's type and ctx's error. If the stack is
+// closed and empty, or is closed while this is waiting, this returns the zero value of the stack's
+// type and ErrClosed. If the stack is nil, this blocks until ctx is done.
+func (s *Stack[T]) PopWait(ctx context.Context) (t T, err error) {
+	if s == nil {
+		<-ctx.Done()
+		return t, ctx.Err()
+	}
+
+	s.mutex.Lock()
+
+	if t, ok := s.pop(); ok {
+		s.mutex.Unlock()
+		return t, nil
+	}
+
+	if s.closed {
+		s.mutex.Unlock()
+		return t, ErrClosed
+	}
+
+	if err := ctx.Err(); err != nil {
+		s.mutex.Unlock()
+		return t, err
+	}
+
+	ch := make(chan T, 1)
+	s.waiters = append(s.waiters, ch)
+	s.mutex.Unlock()
+
+	var ok bool
+	select {
+	case t, ok = <-ch:
+		if !ok {
+			return t, ErrClosed
+		}
+		return t, nil
+	case <-ctx.Done():
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.removeWaiter(ch) {
+		return t, ctx.Err()
+	}
+
+	// Either a push handed us a value or the stack was closed between ctx finishing and us
+	// reacquiring the lock. Returning the value is the only way to not lose it.
+	if t, ok = <-ch; !ok {
+		return t, ErrClosed
+	}
+	return t, nil
+}
+
+// removeWaiter removes ch from the list of waiters and reports whether it was found. The caller
+// must hold the stack's lock.
+func (s *Stack[T]) removeWaiter(ch chan T) bool {
+	for i, w := range s.waiters {
+		if w == ch {
+			copy(s.waiters[i:], s.waiters[i+1:])
+			s.waiters[len(s.waiters)-1] = nil
+			s.waiters = s.waiters[:len(s.waiters)-1]
+
+			return true
+		}
+	}
+
+	return false
 }
 
 // Peek returns the value at the top of the stack without removing it. If the stack is empty, this
@@ -112,5 +423,55 @@ func (s *Stack[T]) Clear() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	s.notify(EventCleared, s.items...)
+	s.stats.touched()
 	s.items = nil
+	s.removed()
+}
+
+// Close marks the stack as closed, signaling that no more values will be pushed onto it. After the
+// stack is closed, pushes fail with ErrClosed, any pushes blocked waiting for room return
+// ErrClosed, and any goroutines blocked in PopWait are released with ErrClosed. Values already on
+// the stack remain and can still be popped; once they are gone, PopWait returns ErrClosed instead
+// of blocking. Every subscription is sent an EventClosed, if its buffer has room, and then ended
+// as if it were cancelled. Closing a closed stack has no effect.
+func (s *Stack[T]) Close() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.notify(EventClosed)
+
+	for i, sub := range s.subscribers {
+		close(sub.events)
+		s.subscribers[i] = nil
+	}
+	s.subscribers = nil
+
+	for i, ch := range s.waiters {
+		close(ch)
+		s.waiters[i] = nil
+	}
+	s.waiters = nil
+
+	s.removed()
+}
+
+// Closed returns true if the stack has been closed.
+func (s *Stack[T]) Closed() bool {
+	if s == nil {
+		return false
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.closed
 }
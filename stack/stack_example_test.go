@@ -1,8 +1,10 @@
 package stack_test
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/green-aloe/utilities/stack"
 )
@@ -20,6 +22,36 @@ func ExampleStack_Push() {
 	// 1 123
 }
 
+func ExampleNewBounded() {
+	s := stack.NewBounded[int](2, stack.OverflowReject)
+
+	err1 := s.TryPush(1)
+	err2 := s.TryPush(2)
+	err3 := s.TryPush(3)
+
+	fmt.Println(err1, err2, err3)
+	fmt.Println(s.Count())
+
+	// Output:
+	// <nil> <nil> stack: full
+	// 2
+}
+
+func ExampleStack_PushContext() {
+	s := stack.NewBounded[string](2, stack.OverflowEvict)
+
+	result1, _ := s.PushContext(context.Background(), "a")
+	result2, _ := s.PushContext(context.Background(), "b")
+	result3, _ := s.PushContext(context.Background(), "c")
+
+	fmt.Println(result1 == stack.Stored, result2 == stack.Stored, result3 == stack.Evicted)
+	fmt.Println(s.Pop(), s.Pop(), s.Pop())
+
+	// Output:
+	// true true true
+	// c b
+}
+
 func ExampleStack_Pop() {
 	var s stack.Stack[string]
 	top1 := s.Pop()
@@ -66,6 +98,28 @@ func ExampleStack_CheckPop() {
 	// 0 false
 }
 
+func ExampleStack_PopWait() {
+	var s stack.Stack[string]
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		s.Push("hello")
+	}()
+
+	top1, err1 := s.PopWait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	top2, err2 := s.PopWait(ctx)
+
+	fmt.Println(top1, err1)
+	fmt.Println(top2, err2)
+
+	// Output:
+	// hello <nil>
+	//  context deadline exceeded
+}
+
 func ExampleStack_Peek() {
 	var s stack.Stack[[]string]
 	top1 := s.Peek()
@@ -140,3 +194,22 @@ func ExampleStack_Clear() {
 	// Output:
 	// 2 0
 }
+
+func ExampleStack_Close() {
+	var s stack.Stack[int]
+	s.Push(1)
+	s.Close()
+
+	err1 := s.TryPush(2)
+	top, err2 := s.PopWait(context.Background())
+	_, err3 := s.PopWait(context.Background())
+
+	fmt.Println(err1)
+	fmt.Println(top, err2)
+	fmt.Println(err3)
+
+	// Output:
+	// stack: closed
+	// 1 <nil>
+	// stack: closed
+}
@@ -1,9 +1,11 @@
 package stack
 
 import (
+	"context"
 	"sort"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -36,6 +38,21 @@ func Test_Stack_Push(t *testing.T) {
 		require.Equal(t, "1", s.Pop())
 	})
 
+	t.Run("full stack", func(t *testing.T) {
+		s := NewBounded[int](1, OverflowReject)
+		s.Push(1)
+		s.Push(2)
+		require.Equal(t, 1, s.Count())
+		require.Equal(t, 1, s.Pop())
+	})
+
+	t.Run("closed stack", func(t *testing.T) {
+		var s Stack[int]
+		s.Close()
+		s.Push(1)
+		require.True(t, s.Empty())
+	})
+
 	t.Run("concurrent use", func(t *testing.T) {
 		var s Stack[int]
 
@@ -53,6 +70,223 @@ func Test_Stack_Push(t *testing.T) {
 	})
 }
 
+// Test_NewBounded tests that NewBounded returns a stack that enforces its capacity with the given
+// overflow policy.
+func Test_NewBounded(t *testing.T) {
+	t.Run("unbounded", func(t *testing.T) {
+		for _, capacity := range []int{0, -1} {
+			s := NewBounded[int](capacity, OverflowReject)
+			for i := 0; i < 100; i++ {
+				require.NoError(t, s.TryPush(i))
+			}
+			require.Equal(t, 100, s.Count())
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		s := NewBounded[int](3, OverflowReject)
+		require.NoError(t, s.TryPush(1))
+		require.NoError(t, s.TryPush(2))
+		require.NoError(t, s.TryPush(3))
+		require.ErrorIs(t, s.TryPush(4), ErrFull)
+		require.Equal(t, 3, s.Count())
+		require.Equal(t, 3, s.Pop())
+
+		require.NoError(t, s.TryPush(5))
+		require.Equal(t, 5, s.Pop())
+		require.Equal(t, 2, s.Pop())
+		require.Equal(t, 1, s.Pop())
+	})
+
+	t.Run("evict", func(t *testing.T) {
+		s := NewBounded[int](3, OverflowEvict)
+		for i := 1; i <= 10; i++ {
+			require.NoError(t, s.TryPush(i))
+			require.LessOrEqual(t, s.Count(), 3)
+		}
+		require.Equal(t, 10, s.Pop())
+		require.Equal(t, 9, s.Pop())
+		require.Equal(t, 8, s.Pop())
+		require.True(t, s.Empty())
+	})
+
+	t.Run("block", func(t *testing.T) {
+		s := NewBounded[int](1, OverflowBlock)
+		s.Push(1)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.Push(2)
+		}()
+		awaitBlockedPush(t, s)
+
+		require.Equal(t, 1, s.Pop())
+		<-done
+		require.Equal(t, 2, s.Pop())
+	})
+}
+
+// Test_Stack_TryPush tests that Stack's TryPush method adds a value to the top of the stack, or
+// reports why it couldn't without waiting, for various stack configurations.
+func Test_Stack_TryPush(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NoError(t, s.TryPush(1))
+	})
+
+	t.Run("unbounded stack", func(t *testing.T) {
+		var s Stack[int]
+		for i := 0; i < 10; i++ {
+			require.NoError(t, s.TryPush(i))
+		}
+		require.Equal(t, 10, s.Count())
+	})
+
+	t.Run("full stack, reject", func(t *testing.T) {
+		s := NewBounded[string](1, OverflowReject)
+		require.NoError(t, s.TryPush("a"))
+		require.ErrorIs(t, s.TryPush("b"), ErrFull)
+		require.Equal(t, "a", s.Peek())
+	})
+
+	t.Run("full stack, evict", func(t *testing.T) {
+		s := NewBounded[string](1, OverflowEvict)
+		require.NoError(t, s.TryPush("a"))
+		require.NoError(t, s.TryPush("b"))
+		require.Equal(t, []string{"b"}, s.Drain())
+	})
+
+	t.Run("full stack, block", func(t *testing.T) {
+		s := NewBounded[string](1, OverflowBlock)
+		require.NoError(t, s.TryPush("a"))
+		require.ErrorIs(t, s.TryPush("b"), ErrFull)
+		require.Equal(t, "a", s.Peek())
+	})
+
+	t.Run("closed stack", func(t *testing.T) {
+		var s Stack[string]
+		s.Close()
+		require.ErrorIs(t, s.TryPush("a"), ErrClosed)
+		require.True(t, s.Empty())
+	})
+}
+
+// Test_Stack_PushContext tests that Stack's PushContext method adds a value to the top of the stack
+// and reports what happened for various stack configurations.
+func Test_Stack_PushContext(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		result, err := s.PushContext(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, Rejected, result)
+	})
+
+	t.Run("unbounded stack", func(t *testing.T) {
+		var s Stack[int]
+		for i := 0; i < 10; i++ {
+			result, err := s.PushContext(context.Background(), i)
+			require.NoError(t, err)
+			require.Equal(t, Stored, result)
+		}
+		require.Equal(t, 10, s.Count())
+	})
+
+	t.Run("full stack, reject", func(t *testing.T) {
+		s := NewBounded[string](1, OverflowReject)
+		result, err := s.PushContext(context.Background(), "a")
+		require.NoError(t, err)
+		require.Equal(t, Stored, result)
+
+		result, err = s.PushContext(context.Background(), "b")
+		require.ErrorIs(t, err, ErrFull)
+		require.Equal(t, Rejected, result)
+		require.Equal(t, "a", s.Peek())
+	})
+
+	t.Run("full stack, evict", func(t *testing.T) {
+		s := NewBounded[string](2, OverflowEvict)
+		result, err := s.PushContext(context.Background(), "a")
+		require.NoError(t, err)
+		require.Equal(t, Stored, result)
+
+		result, err = s.PushContext(context.Background(), "b")
+		require.NoError(t, err)
+		require.Equal(t, Stored, result)
+
+		result, err = s.PushContext(context.Background(), "c")
+		require.NoError(t, err)
+		require.Equal(t, Evicted, result)
+
+		require.Equal(t, 2, s.Count())
+		require.Equal(t, "c", s.Pop())
+		require.Equal(t, "b", s.Pop())
+	})
+
+	t.Run("full stack, block times out", func(t *testing.T) {
+		s := NewBounded[string](1, OverflowBlock)
+		s.Push("a")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		result, err := s.PushContext(ctx, "b")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, Rejected, result)
+		require.Equal(t, 1, s.Count())
+	})
+
+	t.Run("full stack, block until clear", func(t *testing.T) {
+		s := NewBounded[string](1, OverflowBlock)
+		s.Push("a")
+
+		var result PushResult
+		var err error
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			result, err = s.PushContext(context.Background(), "b")
+		}()
+
+		awaitBlockedPush(t, s)
+		s.Clear()
+
+		<-done
+		require.NoError(t, err)
+		require.Equal(t, Stored, result)
+		require.Equal(t, "b", s.Pop())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		s := NewBounded[int](10, OverflowBlock)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				result, err := s.PushContext(context.Background(), i)
+				require.NoError(t, err)
+				require.Equal(t, Stored, result)
+			}(i)
+		}
+
+		var have []int
+		for len(have) < 100 {
+			top, err := s.PopWait(context.Background())
+			require.NoError(t, err)
+			require.LessOrEqual(t, s.Count(), 10)
+			have = append(have, top)
+		}
+		wg.Wait()
+
+		sort.Ints(have)
+		for i, v := range have {
+			require.Equal(t, i, v)
+		}
+	})
+}
+
 // Test_Stack_Pop tests that Stack's Pop method removes and returns the value at the top of the
 // stack for various stack configurations.
 func Test_Stack_Pop(t *testing.T) {
@@ -202,6 +436,164 @@ func Test_Stack_CheckPop(t *testing.T) {
 	})
 }
 
+// Test_Stack_PopWait tests that Stack's PopWait method removes and returns the value at the top of
+// the stack, blocking until a value is available or the context is done, for various stack
+// configurations.
+func Test_Stack_PopWait(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		top, err := s.PopWait(ctx)
+		require.Zero(t, top)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("empty stack, cancelled context", func(t *testing.T) {
+		var s Stack[string]
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		top, err := s.PopWait(ctx)
+		require.Zero(t, top)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("empty stack, context times out", func(t *testing.T) {
+		var s Stack[string]
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		top, err := s.PopWait(ctx)
+		require.Zero(t, top)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// The timed-out waiter must not swallow the next value.
+		s.Push("a")
+		require.Equal(t, 1, s.Count())
+		require.Equal(t, "a", s.Pop())
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[int]
+		for i := 1; i <= 10; i++ {
+			s.Push(i)
+		}
+		for i := 10; i >= 1; i-- {
+			top, err := s.PopWait(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, i, top)
+		}
+		require.True(t, s.Empty())
+	})
+
+	t.Run("non-empty stack, cancelled context", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		top, err := s.PopWait(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, top)
+	})
+
+	t.Run("push wakes waiter", func(t *testing.T) {
+		var s Stack[int]
+
+		ch := make(chan int)
+		go func() {
+			top, err := s.PopWait(context.Background())
+			require.NoError(t, err)
+			ch <- top
+		}()
+
+		awaitWaiters(t, &s, 1)
+		s.Push(42)
+
+		require.Equal(t, 42, <-ch)
+		require.True(t, s.Empty())
+	})
+
+	t.Run("waiters are served in order", func(t *testing.T) {
+		var s Stack[int]
+
+		chs := make([]chan int, 5)
+		for i := range chs {
+			chs[i] = make(chan int, 1)
+			go func(ch chan int) {
+				top, err := s.PopWait(context.Background())
+				require.NoError(t, err)
+				ch <- top
+			}(chs[i])
+			awaitWaiters(t, &s, i+1)
+		}
+
+		for i := range chs {
+			s.Push(i)
+		}
+		for i, ch := range chs {
+			require.Equal(t, i, <-ch)
+		}
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch := make(chan int, 1000)
+		errs := make(chan error, 1000)
+		for i := 0; i < 1000; i++ {
+			go func() {
+				top, err := s.PopWait(ctx)
+				if err != nil {
+					errs <- err
+					return
+				}
+				ch <- top
+			}()
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 1000; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.Push(i)
+				s.Clear()
+			}(i)
+		}
+		wg.Wait()
+
+		// Every push is followed by a Clear, so each value was either handed to a waiter or
+		// dropped by Clear, and nothing is left on the stack.
+		require.True(t, s.Empty())
+
+		// Every waiter either receives a value or, for each value that Clear dropped, stays
+		// parked.
+		awaitBlocked(t, &s, func() bool { return len(s.waiters)+len(ch) == 1000 })
+		s.mutex.Lock()
+		cleared := len(s.waiters)
+		s.mutex.Unlock()
+
+		seen := make(map[int]bool)
+		for len(ch) > 0 {
+			v := <-ch
+			require.False(t, seen[v], "value %d received twice", v)
+			require.True(t, 0 <= v && v < 1000, "value %d never pushed", v)
+			seen[v] = true
+		}
+		require.Len(t, seen, 1000-cleared)
+
+		cancel()
+		for i := 0; i < cleared; i++ {
+			require.ErrorIs(t, <-errs, context.Canceled)
+		}
+	})
+}
+
 // Test_Stack_Peek tests that Stack's Peek method returns the value at the top of the stack without
 // removing it for various stack configurations.
 func Test_Stack_Peek(t *testing.T) {
@@ -428,6 +820,142 @@ func Test_Stack_Clear(t *testing.T) {
 	})
 }
 
+// Test_Stack_Close tests that Stack's Close method stops new values from being pushed, lets existing
+// values drain, and releases blocked goroutines for various stack configurations.
+func Test_Stack_Close(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NotPanics(t, func() { s.Close() })
+		require.False(t, s.Closed())
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		require.False(t, s.Closed())
+		s.Close()
+		require.True(t, s.Closed())
+		require.NotPanics(t, func() { s.Close() })
+		require.True(t, s.Closed())
+
+		require.ErrorIs(t, s.TryPush(1), ErrClosed)
+		require.True(t, s.Empty())
+
+		top, err := s.PopWait(context.Background())
+		require.Zero(t, top)
+		require.ErrorIs(t, err, ErrClosed)
+	})
+
+	t.Run("non-empty stack", func(t *testing.T) {
+		var s Stack[string]
+		s.Push("a")
+		s.Push("b")
+		s.Close()
+
+		result, err := s.PushContext(context.Background(), "c")
+		require.ErrorIs(t, err, ErrClosed)
+		require.Equal(t, Rejected, result)
+
+		top, err := s.PopWait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "b", top)
+
+		top, ok := s.CheckPop()
+		require.True(t, ok)
+		require.Equal(t, "a", top)
+
+		top, ok = s.CheckPop()
+		require.False(t, ok)
+		require.Zero(t, top)
+
+		top, err = s.PopWait(context.Background())
+		require.ErrorIs(t, err, ErrClosed)
+		require.Zero(t, top)
+	})
+
+	t.Run("releases waiting pops", func(t *testing.T) {
+		var s Stack[int]
+
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			go func() {
+				_, err := s.PopWait(context.Background())
+				errs <- err
+			}()
+		}
+
+		awaitWaiters(t, &s, 10)
+		s.Close()
+
+		for i := 0; i < 10; i++ {
+			require.ErrorIs(t, <-errs, ErrClosed)
+		}
+	})
+
+	t.Run("releases waiting pushes", func(t *testing.T) {
+		s := NewBounded[int](1, OverflowBlock)
+		s.Push(1)
+
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			go func() {
+				_, err := s.PushContext(context.Background(), 2)
+				errs <- err
+			}()
+		}
+
+		// Pushes that haven't blocked yet when the stack closes fail with ErrClosed too.
+		awaitBlockedPush(t, s)
+		s.Close()
+
+		for i := 0; i < 10; i++ {
+			require.ErrorIs(t, <-errs, ErrClosed)
+		}
+		require.Equal(t, 1, s.Pop())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+
+		var pushed, popped sync.WaitGroup
+		var mutex sync.Mutex
+		var stored, received int
+		for i := 0; i < 100; i++ {
+			pushed.Add(1)
+			go func(i int) {
+				defer pushed.Done()
+				if s.TryPush(i) == nil {
+					mutex.Lock()
+					stored++
+					mutex.Unlock()
+				}
+			}(i)
+
+			popped.Add(1)
+			go func() {
+				defer popped.Done()
+				for {
+					_, err := s.PopWait(context.Background())
+					if err != nil {
+						require.ErrorIs(t, err, ErrClosed)
+						return
+					}
+					mutex.Lock()
+					received++
+					mutex.Unlock()
+				}
+			}()
+		}
+
+		pushed.Wait()
+		s.Close()
+		popped.Wait()
+
+		// Every value that was accepted must have been received exactly once.
+		require.Equal(t, stored, received)
+		require.True(t, s.Empty())
+	})
+}
+
 // Test_differentTypes tests that Stack can handle values of different types.
 func Test_differentTypes(t *testing.T) {
 	var s Stack[any]
@@ -534,3 +1062,27 @@ func benchmark_operation(b *testing.B, op benchOp, size int) {
 		}
 	}
 }
+
+// awaitWaiters blocks until at least n goroutines are waiting in s.PopWait.
+func awaitWaiters[T any](t *testing.T, s *Stack[T], n int) {
+	t.Helper()
+	awaitBlocked(t, s, func() bool { return len(s.waiters) >= n })
+}
+
+// awaitBlockedPush blocks until a push is waiting for room on s.
+func awaitBlockedPush[T any](t *testing.T, s *Stack[T]) {
+	t.Helper()
+	awaitBlocked(t, s, func() bool { return s.space != nil })
+}
+
+// awaitBlocked blocks until ready, which is called with s locked, returns true. Tests use it
+// instead of sleeping to make sure a goroutine has blocked on the stack before they unblock it.
+func awaitBlocked[T any](t *testing.T, s *Stack[T], ready func() bool) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return ready()
+	}, 10*time.Second, time.Millisecond)
+}
@@ -0,0 +1,165 @@
+package stack
+
+import "time"
+
+// Stats holds operation metrics for a stack, recorded since stats were enabled or last reset.
+type Stats struct {
+	// Pushes is the number of values pushed onto the stack, including values handed directly to
+	// goroutines waiting in PopWait.
+	Pushes uint64
+	// Pops is the number of values popped from the stack by any method, including values handed
+	// directly to goroutines waiting in PopWait.
+	Pops uint64
+	// EmptyPops is the number of times Pop or CheckPop was called on an empty stack.
+	EmptyPops uint64
+	// PeakDepth is the largest number of values the stack has held.
+	PeakDepth int
+	// LastActivity is the last time the stack's contents changed, or the time stats were enabled
+	// or reset if they haven't changed since.
+	LastActivity time.Time
+	// Idle is how long it has been since LastActivity, as of when the stats were read.
+	Idle time.Duration
+}
+
+// stats records operation metrics for a stack. A nil *stats records nothing, so a stack without
+// stats enabled pays only for a nil check on each operation.
+type stats struct {
+	pushes       uint64
+	pops         uint64
+	emptyPops    uint64
+	peakDepth    int
+	lastActivity time.Time
+
+	// now returns the current time.
+	now func() time.Time
+}
+
+// EnableStats starts recording operation metrics for the stack, which can then be read with Stats.
+// Recording adds a small cost to every operation that changes the stack. If stats are already
+// enabled, this has no effect. Otherwise, recording starts from zero, with a peak depth of the
+// stack's current depth.
+func (s *Stack[T]) EnableStats() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stats == nil {
+		s.stats = newStats(len(s.items), time.Now)
+	}
+}
+
+// DisableStats stops recording operation metrics for the stack and discards any that have been
+// recorded.
+func (s *Stack[T]) DisableStats() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.stats = nil
+}
+
+// Stats returns the stack's operation metrics. If stats are not enabled, this returns the zero
+// value.
+func (s *Stack[T]) Stats() Stats {
+	if s == nil {
+		return Stats{}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.stats.snapshot()
+}
+
+// ResetStats returns the stack's operation metrics and resets them to zero, as one atomic
+// operation, so no activity is lost or counted twice between successive calls. The peak depth is
+// reset to the stack's current depth. If stats are not enabled, this returns the zero value and
+// does nothing.
+func (s *Stack[T]) ResetStats() Stats {
+	if s == nil {
+		return Stats{}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stats == nil {
+		return Stats{}
+	}
+
+	stats := s.stats.snapshot()
+	s.stats = newStats(len(s.items), s.stats.now)
+
+	return stats
+}
+
+// newStats returns a new, zeroed set of stats for a stack that currently holds depth values, which
+// tells the time with now.
+func newStats(depth int, now func() time.Time) *stats {
+	return &stats{
+		peakDepth:    depth,
+		lastActivity: now(),
+		now:          now,
+	}
+}
+
+// snapshot returns the recorded metrics as a Stats.
+func (st *stats) snapshot() Stats {
+	if st == nil {
+		return Stats{}
+	}
+
+	return Stats{
+		Pushes:       st.pushes,
+		Pops:         st.pops,
+		EmptyPops:    st.emptyPops,
+		PeakDepth:    st.peakDepth,
+		LastActivity: st.lastActivity,
+		Idle:         st.now().Sub(st.lastActivity),
+	}
+}
+
+// pushed records that n values were pushed, leaving the stack with depth values.
+func (st *stats) pushed(n, depth int) {
+	if st == nil {
+		return
+	}
+
+	st.pushes += uint64(n)
+	st.peakDepth = max(st.peakDepth, depth)
+	st.lastActivity = st.now()
+}
+
+// popped records that n values were popped.
+func (st *stats) popped(n int) {
+	if st == nil {
+		return
+	}
+
+	st.pops += uint64(n)
+	st.lastActivity = st.now()
+}
+
+// emptyPop records an attempt to pop from an empty stack.
+func (st *stats) emptyPop() {
+	if st == nil {
+		return
+	}
+
+	st.emptyPops++
+}
+
+// touched records a change to the stack that is neither a push nor a pop.
+func (st *stats) touched() {
+	if st == nil {
+		return
+	}
+
+	st.lastActivity = st.now()
+}
@@ -0,0 +1,24 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleStack_Stats() {
+	var s stack.Stack[int]
+	s.EnableStats()
+
+	s.PushMany(1, 2, 3)
+	s.Pop()
+	s.Push(4)
+	s.Drain()
+	s.Pop()
+
+	stats := s.Stats()
+	fmt.Println(stats.Pushes, stats.Pops, stats.EmptyPops, stats.PeakDepth)
+
+	// Output:
+	// 4 4 1 3
+}
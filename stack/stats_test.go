@@ -0,0 +1,282 @@
+package stack
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Stack_Stats tests that a stack records operation metrics only while stats are enabled, for
+// various operations.
+func Test_Stack_Stats(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		require.NotPanics(t, func() { s.EnableStats() })
+		require.NotPanics(t, func() { s.DisableStats() })
+		require.Zero(t, s.Stats())
+		require.Zero(t, s.ResetStats())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(1)
+		s.Pop()
+		s.Pop()
+		require.Zero(t, s.Stats())
+		require.Zero(t, s.ResetStats())
+	})
+
+	t.Run("push and pop", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(0)
+		s.EnableStats()
+
+		stats := s.Stats()
+		require.Zero(t, stats.Pushes)
+		require.Equal(t, 1, stats.PeakDepth)
+
+		s.Push(1)
+		s.PushMany(2, 3, 4)
+		s.Pop()
+		s.CheckPop()
+		s.PopN(2)
+		s.Drain()
+		s.Pop()
+		s.CheckPop()
+
+		stats = s.Stats()
+		require.Equal(t, uint64(4), stats.Pushes)
+		require.Equal(t, uint64(5), stats.Pops)
+		require.Equal(t, uint64(2), stats.EmptyPops)
+		require.Equal(t, 5, stats.PeakDepth)
+	})
+
+	t.Run("other operations", func(t *testing.T) {
+		s := NewBounded[int](3, OverflowEvict)
+		s.EnableStats()
+
+		s.PushMany(1, 2, 3, 4)
+		s.Dup()
+		s.Swap()
+		s.PopIf(func(int) bool { return true })
+		s.RemoveFunc(func(v int) bool { return v == 3 })
+		s.Do(func(tx *Tx[int]) error {
+			tx.Push(tx.Pop() * 2)
+			return nil
+		})
+		s.Clear()
+
+		stats := s.Stats()
+		require.Equal(t, uint64(6), stats.Pushes)
+		require.Equal(t, uint64(2), stats.Pops)
+		require.Zero(t, stats.EmptyPops)
+		require.Equal(t, 3, stats.PeakDepth)
+	})
+
+	t.Run("bounded stack, evict", func(t *testing.T) {
+		s := NewBounded[int](3, OverflowEvict)
+		s.EnableStats()
+
+		for i := 0; i < 10; i++ {
+			s.Push(i)
+		}
+		require.Equal(t, 3, s.Stats().PeakDepth)
+
+		s.PushMany(1, 2, 3, 4, 5, 6, 7)
+		require.Equal(t, 3, s.Stats().PeakDepth)
+
+		s.Clear()
+		s.ResetStats()
+		s.Do(func(tx *Tx[int]) error {
+			for i := 0; i < 5; i++ {
+				tx.Push(i)
+			}
+			return nil
+		})
+		require.Equal(t, 3, s.Stats().PeakDepth)
+
+		s.Clear()
+		s.ResetStats()
+		require.NoError(t, s.UnmarshalJSON([]byte(`[1,2,3,4,5]`)))
+		require.Equal(t, 3, s.Stats().PeakDepth)
+		require.Equal(t, uint64(5), s.Stats().Pushes)
+	})
+
+	t.Run("waiters", func(t *testing.T) {
+		var s Stack[int]
+		s.EnableStats()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.PopWait(context.Background())
+		}()
+		awaitWaiters(t, &s, 1)
+		s.Push(1)
+		<-done
+
+		stats := s.Stats()
+		require.Equal(t, uint64(1), stats.Pushes)
+		require.Equal(t, uint64(1), stats.Pops)
+		require.Zero(t, stats.EmptyPops)
+		require.Zero(t, stats.PeakDepth)
+	})
+
+	t.Run("last activity", func(t *testing.T) {
+		var s Stack[int]
+		clock := enableFakeStats(&s)
+		start := clock.Now()
+
+		stats := s.Stats()
+		require.Equal(t, start, stats.LastActivity)
+		require.Zero(t, stats.Idle)
+
+		clock.Advance(time.Minute)
+		require.Equal(t, time.Minute, s.Stats().Idle)
+
+		s.Push(1)
+		stats = s.Stats()
+		require.Equal(t, start.Add(time.Minute), stats.LastActivity)
+		require.Zero(t, stats.Idle)
+
+		clock.Advance(time.Minute)
+		s.Pop()
+		require.Equal(t, start.Add(2*time.Minute), s.Stats().LastActivity)
+
+		clock.Advance(time.Minute)
+		s.ResetStats()
+		stats = s.Stats()
+		require.Equal(t, start.Add(3*time.Minute), stats.LastActivity)
+		require.Zero(t, stats.Idle)
+	})
+
+	t.Run("reads aren't activity", func(t *testing.T) {
+		var s Stack[int]
+		clock := enableFakeStats(&s)
+		s.Push(1)
+		last := s.Stats().LastActivity
+
+		clock.Advance(time.Minute)
+		s.Peek()
+		s.Empty()
+		s.Count()
+		s.Snapshot()
+
+		stats := s.Stats()
+		require.Equal(t, last, stats.LastActivity)
+		require.Equal(t, time.Minute, stats.Idle)
+
+		// A pop is activity, but a pop from an empty stack changes nothing.
+		s.Pop()
+		clock.Advance(time.Minute)
+		s.Pop()
+		require.Equal(t, last.Add(time.Minute), s.Stats().LastActivity)
+	})
+
+	t.Run("enable twice", func(t *testing.T) {
+		var s Stack[int]
+		s.EnableStats()
+		s.Push(1)
+		s.EnableStats()
+		require.Equal(t, uint64(1), s.Stats().Pushes)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		var s Stack[int]
+		s.EnableStats()
+		s.Push(1)
+		s.DisableStats()
+		require.Zero(t, s.Stats())
+
+		s.EnableStats()
+		require.Zero(t, s.Stats().Pushes)
+		require.Equal(t, 1, s.Stats().PeakDepth)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		var s Stack[int]
+		s.EnableStats()
+		s.PushMany(1, 2, 3)
+		s.Pop()
+
+		stats := s.ResetStats()
+		require.Equal(t, uint64(3), stats.Pushes)
+		require.Equal(t, uint64(1), stats.Pops)
+		require.Equal(t, 3, stats.PeakDepth)
+
+		stats = s.Stats()
+		require.Zero(t, stats.Pushes)
+		require.Zero(t, stats.Pops)
+		require.Equal(t, 2, stats.PeakDepth)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+		s.EnableStats()
+
+		var mutex sync.Mutex
+		var total Stats
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					s.Push(j)
+					s.Pop()
+				}
+			}(i)
+			go func() {
+				defer wg.Done()
+				stats := s.ResetStats()
+
+				mutex.Lock()
+				total.Pushes += stats.Pushes
+				total.Pops += stats.Pops
+				mutex.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		// Nothing is lost or counted twice across resets.
+		stats := s.ResetStats()
+		require.Equal(t, uint64(10_000), total.Pushes+stats.Pushes)
+		require.Equal(t, uint64(10_000), total.Pops+stats.Pops)
+	})
+}
+
+// Benchmark_Stats measures the overhead of recording stats on pushes and pops.
+func Benchmark_Stats(b *testing.B) {
+	for _, enabled := range []bool{false, true} {
+		name := "disabled"
+		if enabled {
+			name = "enabled"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			var s Stack[int]
+			if enabled {
+				s.EnableStats()
+			}
+
+			for i := 0; i < b.N; i++ {
+				s.Push(i)
+				s.Pop()
+			}
+		})
+	}
+}
+
+// enableFakeStats enables stats on s with a clock that only moves when told to, and returns the
+// clock.
+func enableFakeStats[T any](s *Stack[T]) *fakeClock {
+	clock := newFakeClock()
+	s.EnableStats()
+	s.stats = newStats(s.Count(), clock.Now)
+
+	return clock
+}
@@ -0,0 +1,170 @@
+package stack
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// DefaultSubscriptionBuffer is the number of events a subscription buffers when Subscribe is called
+// without a positive buffer size.
+const DefaultSubscriptionBuffer = 64
+
+// An EventKind identifies the kind of change described by an Event.
+type EventKind int
+
+const (
+	// EventPushed means values were pushed onto the stack. The event's values are in the order
+	// they were pushed, so the last one ended up on top.
+	EventPushed EventKind = iota + 1
+	// EventPopped means values were popped from the stack. The event's values are in the order
+	// they were popped, so the first one was on top.
+	EventPopped
+	// EventEvicted means values were discarded from the bottom of a bounded stack to make room for
+	// new ones. The event's values are ordered from bottom to top.
+	EventEvicted
+	// EventRemoved means values were removed from the middle of the stack by RemoveFunc. The
+	// event's values are ordered from bottom to top.
+	EventRemoved
+	// EventCleared means every value was removed from the stack by Clear. The event's values are
+	// the ones that were on the stack, ordered from bottom to top.
+	EventCleared
+	// EventClosed means the stack was closed. The event has no values, and it is the last event a
+	// subscription receives before its channel is closed.
+	EventClosed
+)
+
+// An Event describes a change to a stack.
+type Event[T any] struct {
+	Kind EventKind
+	// Values holds the values affected by the change. It is shared between every subscription
+	// that receives the event and must not be modified.
+	Values []T
+}
+
+// A Subscription receives events describing changes to a stack. Events are delivered in the order
+// the changes happened, and replaying them against a copy of the stack's contents keeps the copy
+// in sync. Operations that reorder values, like Swap and Roll, are reported as the affected values
+// being popped and pushed back in their new order, and values that are handed directly to a
+// goroutine waiting in PopWait are reported as being pushed and then popped.
+//
+// Delivering events never blocks the stack. If a subscription's buffer is full when an event is
+// sent, the event is dropped and counted by Dropped. A subscriber that sees Dropped increase has
+// missed changes and should resynchronize, for example with Stack.Snapshot.
+type Subscription[T any] struct {
+	stack   *Stack[T]
+	events  chan Event[T]
+	dropped atomic.Uint64
+}
+
+// Subscribe returns a new subscription to changes to the stack that buffers up to buffer events. If
+// buffer is not positive, the subscription buffers DefaultSubscriptionBuffer events. The
+// subscription receives events until it is cancelled or the stack is closed. If the stack is nil
+// or closed, the subscription's channel is already closed.
+func (s *Stack[T]) Subscribe(buffer int) *Subscription[T] {
+	if buffer <= 0 {
+		buffer = DefaultSubscriptionBuffer
+	}
+
+	sub := &Subscription[T]{
+		stack:  s,
+		events: make(chan Event[T], buffer),
+	}
+
+	if s == nil {
+		close(sub.events)
+		return sub
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		close(sub.events)
+		return sub
+	}
+
+	s.subscribers = append(s.subscribers, sub)
+
+	return sub
+}
+
+// Events returns the channel on which the subscription receives events. The channel is closed when
+// the subscription is cancelled or the stack is closed, so ranging over it ends once the stack is
+// closed and every buffered event has been received.
+func (sub *Subscription[T]) Events() <-chan Event[T] {
+	if sub == nil {
+		return nil
+	}
+
+	return sub.events
+}
+
+// Dropped returns the number of events that have been dropped because the subscription's buffer
+// was full.
+func (sub *Subscription[T]) Dropped() uint64 {
+	if sub == nil {
+		return 0
+	}
+
+	return sub.dropped.Load()
+}
+
+// Cancel stops the subscription from receiving any more events and closes its channel. Events that
+// are already buffered can still be received. Cancelling a cancelled subscription has no effect.
+func (sub *Subscription[T]) Cancel() {
+	if sub == nil || sub.stack == nil {
+		return
+	}
+
+	s := sub.stack
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, other := range s.subscribers {
+		if other == sub {
+			s.subscribers = slices.Delete(s.subscribers, i, i+1)
+			close(sub.events)
+			return
+		}
+	}
+}
+
+// notify sends an event to every subscriber, dropping it for any subscriber whose buffer is full.
+// The caller must hold the stack's lock.
+func (s *Stack[T]) notify(kind EventKind, values ...T) {
+	if len(s.subscribers) == 0 {
+		return
+	}
+
+	s.broadcast(Event[T]{
+		Kind:   kind,
+		Values: slices.Clone(values),
+	})
+}
+
+// broadcast sends an event to every subscriber, dropping it for any subscriber whose buffer is
+// full. The caller must hold the stack's lock.
+func (s *Stack[T]) broadcast(event Event[T]) {
+	for _, sub := range s.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// notifyPopped sends an EventPopped for values that are ordered from bottom to top, as they are on
+// the stack. The caller must hold the stack's lock.
+func (s *Stack[T]) notifyPopped(values []T) {
+	if len(s.subscribers) == 0 || len(values) == 0 {
+		return
+	}
+
+	popped := slices.Clone(values)
+	slices.Reverse(popped)
+	s.broadcast(Event[T]{
+		Kind:   EventPopped,
+		Values: popped,
+	})
+}
@@ -0,0 +1,33 @@
+package stack_test
+
+import (
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleStack_Subscribe() {
+	var s stack.Stack[string]
+	sub := s.Subscribe(10)
+
+	s.PushMany("a", "b")
+	s.Pop()
+	s.Clear()
+	sub.Cancel()
+
+	for event := range sub.Events() {
+		switch event.Kind {
+		case stack.EventPushed:
+			fmt.Println("pushed", event.Values)
+		case stack.EventPopped:
+			fmt.Println("popped", event.Values)
+		case stack.EventCleared:
+			fmt.Println("cleared", event.Values)
+		}
+	}
+
+	// Output:
+	// pushed [a b]
+	// popped [b]
+	// cleared [a]
+}
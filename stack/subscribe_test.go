@@ -0,0 +1,382 @@
+package stack
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Stack_Subscribe tests that Stack's Subscribe method returns a subscription that receives an
+// event for every change to the stack for various operations.
+func Test_Stack_Subscribe(t *testing.T) {
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		sub := s.Subscribe(1)
+		_, ok := <-sub.Events()
+		require.False(t, ok)
+		require.NotPanics(t, func() { sub.Cancel() })
+	})
+
+	t.Run("default buffer", func(t *testing.T) {
+		var s Stack[int]
+		sub := s.Subscribe(0)
+		require.Equal(t, DefaultSubscriptionBuffer, cap(sub.events))
+	})
+
+	t.Run("push and pop", func(t *testing.T) {
+		var s Stack[int]
+		sub := s.Subscribe(10)
+
+		s.Push(1)
+		s.Push(2)
+		s.Pop()
+		s.CheckPop()
+		s.Pop()
+
+		requireEvents(t, sub,
+			Event[int]{EventPushed, []int{1}},
+			Event[int]{EventPushed, []int{2}},
+			Event[int]{EventPopped, []int{2}},
+			Event[int]{EventPopped, []int{1}},
+		)
+	})
+
+	t.Run("batch operations", func(t *testing.T) {
+		var s Stack[int]
+		sub := s.Subscribe(10)
+
+		s.PushMany(1, 2, 3, 4)
+		s.PopN(2)
+		s.Drain()
+
+		requireEvents(t, sub,
+			Event[int]{EventPushed, []int{1, 2, 3, 4}},
+			Event[int]{EventPopped, []int{4, 3}},
+			Event[int]{EventPopped, []int{2, 1}},
+		)
+	})
+
+	t.Run("waiters", func(t *testing.T) {
+		var s Stack[int]
+		sub := s.Subscribe(10)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.PopWait(context.Background())
+		}()
+		awaitWaiters(t, &s, 1)
+
+		s.PushMany(1, 2)
+		<-done
+
+		requireEvents(t, sub,
+			Event[int]{EventPushed, []int{1}},
+			Event[int]{EventPopped, []int{1}},
+			Event[int]{EventPushed, []int{2}},
+		)
+	})
+
+	t.Run("waiters and clear, concurrent use", func(t *testing.T) {
+		var s Stack[int]
+		sub := s.Subscribe(4000)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch := make(chan int, 1000)
+		for i := 0; i < 1000; i++ {
+			go func() {
+				if top, err := s.PopWait(ctx); err == nil {
+					ch <- top
+				}
+			}()
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 1000; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.Push(i)
+				s.Clear()
+			}(i)
+		}
+		wg.Wait()
+		sub.Cancel()
+		require.Zero(t, sub.Dropped())
+
+		// Every value is pushed once and then either popped by a waiter or cleared.
+		var pushed, popped, cleared []int
+		for e := range sub.Events() {
+			switch e.Kind {
+			case EventPushed:
+				pushed = append(pushed, e.Values...)
+			case EventPopped:
+				popped = append(popped, e.Values...)
+			case EventCleared:
+				cleared = append(cleared, e.Values...)
+			}
+		}
+		want := make([]int, 1000)
+		for i := range want {
+			want[i] = i
+		}
+		slices.Sort(pushed)
+		require.Equal(t, want, pushed)
+		removed := append(slices.Clone(popped), cleared...)
+		slices.Sort(removed)
+		require.Equal(t, want, removed)
+		slices.Sort(popped)
+
+		// The waiters received exactly the popped values, and one is still waiting for each
+		// cleared value.
+		have := make([]int, 0, len(popped))
+		for len(have) < len(popped) {
+			have = append(have, <-ch)
+		}
+		slices.Sort(have)
+		require.Equal(t, popped, have)
+		awaitWaiters(t, &s, len(cleared))
+	})
+
+	t.Run("evict", func(t *testing.T) {
+		s := NewBounded[int](2, OverflowEvict)
+		sub := s.Subscribe(10)
+
+		s.PushMany(1, 2)
+		s.PushMany(3, 4, 5)
+
+		requireEvents(t, sub,
+			Event[int]{EventPushed, []int{1, 2}},
+			Event[int]{EventPushed, []int{3, 4, 5}},
+			Event[int]{EventEvicted, []int{1, 2, 3}},
+		)
+	})
+
+	t.Run("clear, remove and close", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3, 4)
+		sub := s.Subscribe(10)
+
+		s.RemoveFunc(func(v int) bool { return v%2 == 0 })
+		s.Clear()
+		s.Close()
+
+		requireEvents(t, sub,
+			Event[int]{EventRemoved, []int{2, 4}},
+			Event[int]{EventCleared, []int{1, 3}},
+			Event[int]{EventClosed, nil},
+		)
+
+		_, ok := <-sub.Events()
+		require.False(t, ok)
+		require.NotPanics(t, func() { sub.Cancel() })
+	})
+
+	t.Run("close with full buffer", func(t *testing.T) {
+		var s Stack[int]
+		sub := s.Subscribe(1)
+		s.Push(1)
+		s.Close()
+
+		done := make(chan []Event[int])
+		go func() {
+			var have []Event[int]
+			for e := range sub.Events() {
+				have = append(have, e)
+			}
+			done <- have
+		}()
+
+		select {
+		case have := <-done:
+			require.Equal(t, []Event[int]{{EventPushed, []int{1}}}, have)
+		case <-time.After(10 * time.Second):
+			t.Fatal("subscription wasn't closed")
+		}
+		require.Equal(t, uint64(1), sub.Dropped())
+	})
+
+	t.Run("closed stack", func(t *testing.T) {
+		var s Stack[int]
+		s.Close()
+
+		sub := s.Subscribe(1)
+		_, ok := <-sub.Events()
+		require.False(t, ok)
+		require.NotPanics(t, func() { sub.Cancel() })
+	})
+
+	t.Run("reordering", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b", "c")
+		sub := s.Subscribe(10)
+
+		s.Rot()
+		s.Roll(0)
+		s.Dup()
+
+		requireEvents(t, sub,
+			Event[string]{EventPopped, []string{"c", "b", "a"}},
+			Event[string]{EventPushed, []string{"b", "c", "a"}},
+			Event[string]{EventPushed, []string{"a"}},
+		)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+		sub := s.Subscribe(10)
+
+		s.Do(func(tx *Tx[int]) error {
+			tx.Push(tx.Pop() + tx.Pop())
+			return nil
+		})
+		s.Do(func(tx *Tx[int]) error {
+			tx.Pop()
+			return context.Canceled
+		})
+
+		requireEvents(t, sub,
+			Event[int]{EventPopped, []int{3, 2}},
+			Event[int]{EventPushed, []int{5}},
+		)
+	})
+
+	t.Run("event values are copies", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+		sub := s.Subscribe(10)
+
+		s.RemoveFunc(func(v int) bool { return v == 2 })
+		s.Push(4)
+		s.Push(5)
+
+		event := <-sub.Events()
+		require.Equal(t, []int{2}, event.Values)
+	})
+
+	t.Run("slow subscriber", func(t *testing.T) {
+		var s Stack[int]
+		sub := s.Subscribe(2)
+
+		for i := 0; i < 5; i++ {
+			s.Push(i)
+		}
+		require.Equal(t, uint64(3), sub.Dropped())
+		requireEvents(t, sub,
+			Event[int]{EventPushed, []int{0}},
+			Event[int]{EventPushed, []int{1}},
+		)
+		require.Equal(t, 5, s.Count())
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		var s Stack[int]
+		sub1 := s.Subscribe(10)
+		sub2 := s.Subscribe(10)
+
+		s.Push(1)
+		sub1.Cancel()
+		sub1.Cancel()
+		s.Push(2)
+
+		event, ok := <-sub1.Events()
+		require.True(t, ok)
+		require.Equal(t, []int{1}, event.Values)
+		_, ok = <-sub1.Events()
+		require.False(t, ok)
+
+		requireEvents(t, sub2,
+			Event[int]{EventPushed, []int{1}},
+			Event[int]{EventPushed, []int{2}},
+		)
+	})
+
+	t.Run("mirror", func(t *testing.T) {
+		s := NewBounded[int](50, OverflowEvict)
+		sub := s.Subscribe(100_000)
+
+		var wg sync.WaitGroup
+		for g := 0; g < 10; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					switch rand.IntN(10) {
+					case 0, 1, 2:
+						s.Push(i)
+					case 3:
+						s.PushMany(i, i+1, i+2)
+					case 4, 5:
+						s.Pop()
+					case 6:
+						s.PopN(2)
+					case 7:
+						s.Swap()
+					case 8:
+						s.RemoveFunc(func(v int) bool { return v%7 == 0 })
+					case 9:
+						s.Do(func(tx *Tx[int]) error {
+							tx.Push(tx.Pop() + 1)
+							return nil
+						})
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		sub.Cancel()
+		require.Zero(t, sub.Dropped())
+
+		// Replaying every event must reproduce the stack's final contents.
+		var mirror []int
+		for event := range sub.Events() {
+			switch event.Kind {
+			case EventPushed:
+				mirror = append(mirror, event.Values...)
+			case EventPopped:
+				for _, v := range event.Values {
+					require.Equal(t, mirror[len(mirror)-1], v)
+					mirror = mirror[:len(mirror)-1]
+				}
+			case EventEvicted:
+				require.Equal(t, mirror[:len(event.Values)], event.Values)
+				mirror = mirror[len(event.Values):]
+			case EventRemoved:
+				mirror = slices.DeleteFunc(mirror, func(v int) bool { return v%7 == 0 })
+			case EventCleared:
+				mirror = nil
+			}
+		}
+		require.Equal(t, s.Snapshot(), append([]int{}, mirror...))
+	})
+}
+
+// requireEvents asserts that the next events received by sub are exactly want, and that no other
+// events are waiting.
+func requireEvents[T any](t *testing.T, sub *Subscription[T], want ...Event[T]) {
+	t.Helper()
+
+	for _, w := range want {
+		select {
+		case have := <-sub.Events():
+			require.Equal(t, w, have)
+		default:
+			t.Fatalf("missing event %+v", w)
+		}
+	}
+
+	select {
+	case have, ok := <-sub.Events():
+		if ok {
+			t.Fatalf("unexpected event %+v", have)
+		}
+	default:
+	}
+}
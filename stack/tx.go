@@ -0,0 +1,143 @@
+package stack
+
+// A Tx is a view of a stack inside a transaction started with Stack.Do. Changes made through a Tx
+// are only applied to the stack if the transaction commits. A Tx must not be used after the
+// function it was passed to returns.
+type Tx[T any] struct {
+	// base is the stack's items when the transaction started. It is never modified.
+	base []T
+	// keep is the number of values at the bottom of base that the transaction hasn't popped.
+	keep int
+	// pushed holds the values the transaction has pushed and not popped, bottom to top.
+	pushed []T
+}
+
+// Do runs fn as a transaction on the stack. The stack is locked for the duration of fn, so no other
+// goroutine can observe or modify the stack until fn returns, and every operation fn performs
+// through tx sees a consistent view of it. If fn returns nil, all of its changes are applied to the
+// stack at once. If fn returns an error or panics, none of them are, and the error is returned or
+// the panic is propagated.
+//
+// The transaction also fails without changing the stack if it would leave more values on a bounded
+// stack than it can hold, in which case this returns ErrFull (unless the stack's overflow policy is
+// OverflowEvict, in which case values are evicted from the bottom as usual), or if it pushes values
+// onto a closed stack, in which case this returns ErrClosed.
+//
+// fn must not call any methods on the stack itself, because the stack is locked while fn runs. If
+// the stack is nil, fn runs against an empty view whose changes are discarded.
+func (s *Stack[T]) Do(fn func(tx *Tx[T]) error) error {
+	if fn == nil {
+		return nil
+	}
+
+	if s == nil {
+		return fn(&Tx[T]{})
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tx := Tx[T]{
+		base: s.items,
+		keep: len(s.items),
+	}
+	if err := fn(&tx); err != nil {
+		return err
+	}
+
+	return s.commit(&tx)
+}
+
+// commit applies a transaction's changes to the stack. The caller must hold the stack's lock.
+func (s *Stack[T]) commit(tx *Tx[T]) error {
+	if len(tx.pushed) > 0 {
+		if s.closed {
+			return ErrClosed
+		}
+
+		// Values handed to waiters don't take up any room.
+		stored := len(tx.pushed) - min(len(s.waiters), len(tx.pushed))
+		if s.capacity > 0 && tx.keep+stored > s.capacity && s.policy != OverflowEvict {
+			return ErrFull
+		}
+	}
+
+	if tx.keep < len(s.items) {
+		s.notifyPopped(s.items[tx.keep:])
+		s.stats.popped(len(s.items) - tx.keep)
+		clear(s.items[tx.keep:])
+		s.items = s.items[:tx.keep]
+		s.removed()
+	}
+
+	s.storeAll(tx.pushed)
+	s.evict()
+
+	return nil
+}
+
+// Push adds a value to the top of the transaction's view of the stack.
+func (tx *Tx[T]) Push(v T) {
+	if tx == nil {
+		return
+	}
+
+	tx.pushed = append(tx.pushed, v)
+}
+
+// Pop removes and returns the value at the top of the transaction's view of the stack. If the view
+// is empty, this returns the zero value of the stack's type.
+func (tx *Tx[T]) Pop() (t T) {
+	t, _ = tx.CheckPop()
+	return t
+}
+
+// CheckPop removes and returns the value at the top of the transaction's view of the stack and a
+// boolean indicating whether there was one. If the view is empty, this returns the zero value of
+// the stack's type and false.
+func (tx *Tx[T]) CheckPop() (t T, ok bool) {
+	if tx == nil {
+		return
+	}
+
+	if n := len(tx.pushed); n > 0 {
+		t, tx.pushed[n-1] = tx.pushed[n-1], t
+		tx.pushed = tx.pushed[:n-1]
+
+		return t, true
+	}
+
+	if tx.keep > 0 {
+		tx.keep--
+		return tx.base[tx.keep], true
+	}
+
+	return
+}
+
+// Peek returns the value at the top of the transaction's view of the stack without removing it. If
+// the view is empty, this returns the zero value of the stack's type.
+func (tx *Tx[T]) Peek() (t T) {
+	if tx == nil {
+		return
+	}
+
+	if n := len(tx.pushed); n > 0 {
+		return tx.pushed[n-1]
+	}
+
+	if tx.keep > 0 {
+		return tx.base[tx.keep-1]
+	}
+
+	return
+}
+
+// Len returns the number of values in the transaction's view of the stack.
+func (tx *Tx[T]) Len() int {
+	if tx == nil {
+		return 0
+	}
+
+	return tx.keep + len(tx.pushed)
+}
@@ -0,0 +1,38 @@
+package stack_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+func ExampleStack_Do() {
+	var s stack.Stack[int]
+	s.PushMany(1, 2, 3)
+
+	// Replace the top two values with their sum, but only if it's small enough.
+	add := func(tx *stack.Tx[int]) error {
+		if tx.Len() < 2 {
+			return errors.New("not enough values")
+		}
+
+		sum := tx.Pop() + tx.Pop()
+		if sum > 5 {
+			return errors.New("sum too large")
+		}
+		tx.Push(sum)
+
+		return nil
+	}
+
+	err1 := s.Do(add)
+	err2 := s.Do(add)
+
+	fmt.Println(err1, err2)
+	fmt.Println(s.Drain())
+
+	// Output:
+	// <nil> sum too large
+	// [5 1]
+}
@@ -0,0 +1,247 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Stack_Do tests that Stack's Do method applies a transaction's changes all at once or not at
+// all for various stack configurations.
+func Test_Stack_Do(t *testing.T) {
+	errTest := errors.New("test error")
+
+	t.Run("nil stack", func(t *testing.T) {
+		var s *Stack[int]
+		err := s.Do(func(tx *Tx[int]) error {
+			tx.Push(1)
+			require.Equal(t, 1, tx.Len())
+			return nil
+		})
+		require.NoError(t, err)
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("nil function", func(t *testing.T) {
+		var s Stack[int]
+		require.NoError(t, s.Do(nil))
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		var s Stack[int]
+		err := s.Do(func(tx *Tx[int]) error {
+			require.Zero(t, tx.Len())
+			require.Zero(t, tx.Peek())
+
+			top, ok := tx.CheckPop()
+			require.Zero(t, top)
+			require.False(t, ok)
+
+			tx.Push(1)
+			tx.Push(2)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 1}, s.Drain())
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+
+		// Peek two, pop both, push their sum.
+		err := s.Do(func(tx *Tx[int]) error {
+			a := tx.Pop()
+			b := tx.Pop()
+			tx.Push(a + b)
+			require.Equal(t, 2, tx.Len())
+			require.Equal(t, 5, tx.Peek())
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{5, 1}, s.Drain())
+	})
+
+	t.Run("pop through pushed values", func(t *testing.T) {
+		var s Stack[string]
+		s.PushMany("a", "b")
+
+		err := s.Do(func(tx *Tx[string]) error {
+			tx.Push("c")
+			require.Equal(t, "c", tx.Pop())
+			require.Equal(t, "b", tx.Pop())
+			require.Equal(t, "a", tx.Pop())
+			require.Zero(t, tx.Pop())
+			tx.Push("d")
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"d"}, s.Drain())
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+
+		err := s.Do(func(tx *Tx[int]) error {
+			tx.Pop()
+			tx.Pop()
+			tx.Push(10)
+			return errTest
+		})
+		require.ErrorIs(t, err, errTest)
+		require.Equal(t, []int{3, 2, 1}, s.Drain())
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2, 3)
+
+		require.Panics(t, func() {
+			s.Do(func(tx *Tx[int]) error {
+				tx.Pop()
+				tx.Push(10)
+				panic("test panic")
+			})
+		})
+		require.Equal(t, []int{3, 2, 1}, s.Drain())
+
+		// The stack must have been unlocked.
+		s.Push(4)
+		require.Equal(t, 4, s.Pop())
+	})
+
+	t.Run("closed stack", func(t *testing.T) {
+		var s Stack[int]
+		s.PushMany(1, 2)
+		s.Close()
+
+		err := s.Do(func(tx *Tx[int]) error {
+			tx.Pop()
+			tx.Push(3)
+			return nil
+		})
+		require.ErrorIs(t, err, ErrClosed)
+		require.Equal(t, 2, s.Count())
+
+		err = s.Do(func(tx *Tx[int]) error {
+			tx.Pop()
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{1}, s.Drain())
+	})
+
+	t.Run("bounded stack, reject", func(t *testing.T) {
+		s := NewBounded[int](2, OverflowReject)
+		s.PushMany(1, 2)
+
+		err := s.Do(func(tx *Tx[int]) error {
+			tx.Push(3)
+			return nil
+		})
+		require.ErrorIs(t, err, ErrFull)
+
+		err = s.Do(func(tx *Tx[int]) error {
+			tx.Pop()
+			tx.Push(3)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{3, 1}, s.Drain())
+	})
+
+	t.Run("bounded stack, evict", func(t *testing.T) {
+		s := NewBounded[int](2, OverflowEvict)
+		s.PushMany(1, 2)
+
+		err := s.Do(func(tx *Tx[int]) error {
+			tx.Push(3)
+			tx.Push(4)
+			tx.Push(5)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{5, 4}, s.Drain())
+	})
+
+	t.Run("waiters", func(t *testing.T) {
+		var s Stack[int]
+
+		ch := make(chan int)
+		go func() {
+			top, err := s.PopWait(context.Background())
+			require.NoError(t, err)
+			ch <- top
+		}()
+		awaitWaiters(t, &s, 1)
+
+		err := s.Do(func(tx *Tx[int]) error {
+			tx.Push(1)
+			tx.Push(2)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, <-ch)
+		require.Equal(t, []int{2}, s.Drain())
+	})
+
+	t.Run("wakes blocked push", func(t *testing.T) {
+		s := NewBounded[int](1, OverflowBlock)
+		s.Push(1)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.Push(2)
+		}()
+		awaitBlockedPush(t, s)
+
+		err := s.Do(func(tx *Tx[int]) error {
+			tx.Pop()
+			return nil
+		})
+		require.NoError(t, err)
+		<-done
+		require.Equal(t, []int{2}, s.Drain())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var s Stack[int]
+		s.Push(0)
+
+		// Every transaction replaces the top value with its successor, so if any of them
+		// interleave, increments are lost.
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := s.Do(func(tx *Tx[int]) error {
+					tx.Push(tx.Pop() + 1)
+					return nil
+				})
+				require.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, []int{100}, s.Drain())
+	})
+}
+
+// Test_Tx_nil tests that a nil Tx behaves like an empty, read-only view.
+func Test_Tx_nil(t *testing.T) {
+	var tx *Tx[int]
+	require.NotPanics(t, func() { tx.Push(1) })
+	require.Zero(t, tx.Pop())
+	require.Zero(t, tx.Peek())
+	require.Zero(t, tx.Len())
+
+	top, ok := tx.CheckPop()
+	require.Zero(t, top)
+	require.False(t, ok)
+}
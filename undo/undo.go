@@ -0,0 +1,464 @@
+// Package undo provides an undo/redo history of commands.
+package undo
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/green-aloe/utilities/stack"
+)
+
+var (
+	// ErrNothingToUndo is returned by Undo when there is no command to undo.
+	ErrNothingToUndo = errors.New("undo: nothing to undo")
+	// ErrNothingToRedo is returned by Redo when there is no command to redo.
+	ErrNothingToRedo = errors.New("undo: nothing to redo")
+	// ErrGroupOpen is returned when the history is moved while a group is open.
+	ErrGroupOpen = errors.New("undo: group open")
+	// ErrUnknownCheckpoint is returned by RevertTo when a checkpoint's state can no longer be
+	// reached, because the commands leading to it were discarded.
+	ErrUnknownCheckpoint = errors.New("undo: unknown checkpoint")
+)
+
+// A History records commands as they are done so they can be undone and redone. Every entry in the
+// history is a group of one or more commands that are undone and redone together. The zero value
+// is an empty history with no maximum depth and is ready to use. A history is safe for concurrent
+// use.
+//
+// Apply, Revert, and Merge are called while the history is locked, so they must not use it.
+type History[T any] struct {
+	// Apply carries out a command. It is called by Do and Redo. If it is nil, commands are only
+	// recorded.
+	Apply func(T) error
+	// Revert reverses a command. It is called by Undo. If it is nil, commands are only recorded.
+	Revert func(T) error
+	// Merge combines a command with the one done just before it, if possible, so they are undone
+	// as one. It returns the combined command and true, or false if the commands can't be
+	// combined. It is only called once next has been applied. If it is nil, commands are never
+	// merged.
+	Merge func(prev, next T) (T, bool)
+
+	// undo holds entries that can be undone, oldest at the bottom, and redo holds entries that
+	// have been undone, most recently undone on top.
+	undo *stack.Stack[entry[T]]
+	redo stack.Stack[entry[T]]
+
+	maxDepth int
+
+	// lastID is the most recently assigned entry ID. Each entry's ID identifies the state of the
+	// history just after it was done, and base identifies the state when nothing can be undone.
+	lastID uint64
+	base   uint64
+
+	// saved identifies the state last marked as saved.
+	saved uint64
+
+	// group holds the commands done since the outermost open group began, and depth is the
+	// number of open groups.
+	group []T
+	depth int
+
+	mutex sync.Mutex
+}
+
+// An entry is a group of commands, in the order they were done. A sealed entry has had a
+// checkpoint taken just after it, so no more commands may be merged into it.
+type entry[T any] struct {
+	cmds   []T
+	id     uint64
+	sealed bool
+}
+
+// A Checkpoint identifies a state of a history that it can later return to.
+type Checkpoint struct {
+	id uint64
+}
+
+// New returns a new, empty history that holds at most maxDepth entries that can be undone. Once the
+// history is full, doing another command discards the oldest entry. If maxDepth is not positive,
+// the history has no maximum depth.
+func New[T any](maxDepth int) *History[T] {
+	h := &History[T]{
+		maxDepth: max(maxDepth, 0),
+	}
+	h.undo = stack.NewBounded[entry[T]](h.maxDepth, stack.OverflowEvict)
+
+	return h
+}
+
+// Do applies a command and records it in the history, discarding any commands that were undone
+// and not redone. If Apply returns an error, the command isn't recorded and this returns the
+// error. If a group is open, the command joins it; otherwise, it is merged into the previous entry
+// if possible, or becomes a new entry.
+func (h *History[T]) Do(cmd T) error {
+	if h == nil {
+		return nil
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.Apply != nil {
+		if err := h.Apply(cmd); err != nil {
+			return err
+		}
+	}
+
+	h.redo.Clear()
+
+	if h.depth > 0 {
+		h.group = h.merge(h.group, cmd)
+		return nil
+	}
+
+	if top, ok := h.stack().CheckPop(); ok {
+		if !top.sealed && len(top.cmds) == 1 {
+			if cmds := h.merge(top.cmds, cmd); len(cmds) == 1 {
+				h.record(cmds)
+				return nil
+			}
+		}
+		h.stack().Push(top)
+	}
+
+	h.record([]T{cmd})
+
+	return nil
+}
+
+// merge appends cmd to cmds, merging it into the last command if possible. The caller must hold
+// the history's lock.
+func (h *History[T]) merge(cmds []T, cmd T) []T {
+	if n := len(cmds); n > 0 && h.Merge != nil {
+		if merged, ok := h.Merge(cmds[n-1], cmd); ok {
+			cmds[n-1] = merged
+			return cmds
+		}
+	}
+
+	return append(cmds, cmd)
+}
+
+// record adds a new entry to the history for cmds, discarding the oldest entry if the history is
+// full. The caller must hold the history's lock.
+func (h *History[T]) record(cmds []T) {
+	undo := h.stack()
+	if n := undo.Count(); h.maxDepth > 0 && n >= h.maxDepth {
+		// The bottom entry is about to be evicted, so the state after it becomes the oldest one
+		// that can be reached.
+		if bottom, err := undo.PeekAt(n - 1); err == nil {
+			h.base = bottom.id
+		}
+	}
+
+	h.lastID++
+	undo.Push(entry[T]{cmds: cmds, id: h.lastID})
+}
+
+// BeginGroup opens a group, so commands done until the matching EndGroup form one entry in the
+// history. Groups can be nested, in which case the outermost group determines the entry. Groups
+// belong to the history rather than a goroutine, so commands done by any goroutine while a group
+// is open join it.
+func (h *History[T]) BeginGroup() {
+	if h == nil {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.depth++
+}
+
+// EndGroup closes the group opened by the most recent BeginGroup. If that was the outermost group
+// and any commands were done in it, they are recorded as one entry. If no group is open, this has
+// no effect.
+func (h *History[T]) EndGroup() {
+	if h == nil {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.depth == 0 {
+		return
+	}
+
+	h.depth--
+	if h.depth > 0 || len(h.group) == 0 {
+		return
+	}
+
+	h.record(h.group)
+	h.group = nil
+}
+
+// Undo reverts the most recent entry in the history, calling Revert on its commands from last to
+// first, and makes it available to Redo. If Revert returns an error, the commands already reverted
+// are applied again, the entry stays where it is, and this returns the error. If there is nothing
+// to undo, this returns ErrNothingToUndo. If a group is open, this returns ErrGroupOpen.
+func (h *History[T]) Undo() error {
+	if h == nil {
+		return ErrNothingToUndo
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.depth > 0 {
+		return ErrGroupOpen
+	}
+
+	return h.step()
+}
+
+// Redo applies the most recently undone entry again, calling Apply on its commands from first to
+// last. If Apply returns an error, the commands already applied are reverted again, the entry
+// stays where it is, and this returns the error. If there is nothing to redo, this returns
+// ErrNothingToRedo. If a group is open, this returns ErrGroupOpen.
+func (h *History[T]) Redo() error {
+	if h == nil {
+		return ErrNothingToRedo
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.depth > 0 {
+		return ErrGroupOpen
+	}
+
+	return h.restep()
+}
+
+// step undoes the most recent entry. The caller must hold the history's lock.
+func (h *History[T]) step() error {
+	e, ok := h.stack().CheckPop()
+	if !ok {
+		return ErrNothingToUndo
+	}
+
+	if err := run(e.cmds, true, h.Revert, h.Apply); err != nil {
+		h.stack().Push(e)
+		return err
+	}
+
+	h.redo.Push(e)
+
+	return nil
+}
+
+// restep redoes the most recently undone entry. The caller must hold the history's lock.
+func (h *History[T]) restep() error {
+	e, ok := h.redo.CheckPop()
+	if !ok {
+		return ErrNothingToRedo
+	}
+
+	if err := run(e.cmds, false, h.Apply, h.Revert); err != nil {
+		h.redo.Push(e)
+		return err
+	}
+
+	// A redone entry never makes the history deeper than it was before the entry was undone, so
+	// it is never evicted.
+	h.stack().Push(e)
+
+	return nil
+}
+
+// run calls do on each command, from last to first if backward is true. If do returns an error,
+// run calls undo on the commands already done, in the opposite order, and returns the error. If do
+// is nil, run does nothing.
+func run[T any](cmds []T, backward bool, do, undo func(T) error) error {
+	if do == nil {
+		return nil
+	}
+
+	at := func(i int) T {
+		if backward {
+			return cmds[len(cmds)-1-i]
+		}
+		return cmds[i]
+	}
+
+	for i := range cmds {
+		if err := do(at(i)); err != nil {
+			if undo != nil {
+				for j := i - 1; j >= 0; j-- {
+					undo(at(j))
+				}
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CanUndo returns true if there is an entry to undo.
+func (h *History[T]) CanUndo() bool {
+	if h == nil {
+		return false
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return !h.stack().Empty()
+}
+
+// CanRedo returns true if there is an entry to redo.
+func (h *History[T]) CanRedo() bool {
+	if h == nil {
+		return false
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return !h.redo.Empty()
+}
+
+// Checkpoint returns a checkpoint for the history's current state. Commands done after this are
+// never merged into the entry before it.
+func (h *History[T]) Checkpoint() Checkpoint {
+	if h == nil {
+		return Checkpoint{}
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.checkpoint()
+}
+
+// checkpoint returns a checkpoint for the history's current state and seals the entry that led to
+// it. The caller must hold the history's lock.
+func (h *History[T]) checkpoint() Checkpoint {
+	top, ok := h.stack().CheckPop()
+	if !ok {
+		return Checkpoint{id: h.base}
+	}
+
+	top.sealed = true
+	h.stack().Push(top)
+
+	return Checkpoint{id: top.id}
+}
+
+// RevertTo undoes or redoes entries until the history is back at the state cp was taken in. If
+// that state can no longer be reached, because the entries leading to it were discarded, this
+// returns ErrUnknownCheckpoint and does nothing. If undoing or redoing an entry fails, this stops
+// there and returns the error. If a group is open, this returns ErrGroupOpen.
+func (h *History[T]) RevertTo(cp Checkpoint) error {
+	if h == nil {
+		return ErrUnknownCheckpoint
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.depth > 0 {
+		return ErrGroupOpen
+	}
+
+	match := func(e entry[T]) bool { return e.id == cp.id }
+
+	var steps int
+	var step func() error
+	switch {
+	case cp.id == h.current():
+		return nil
+	case cp.id == h.base:
+		steps, step = h.stack().Count(), h.step
+	default:
+		if depth, ok := h.stack().Search(match); ok {
+			steps, step = depth, h.step
+		} else if depth, ok := h.redo.Search(match); ok {
+			steps, step = depth+1, h.restep
+		} else {
+			return ErrUnknownCheckpoint
+		}
+	}
+
+	for i := 0; i < steps; i++ {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarkSaved records the history's current state as saved, so it is no longer dirty. It returns a
+// checkpoint for that state.
+func (h *History[T]) MarkSaved() Checkpoint {
+	if h == nil {
+		return Checkpoint{}
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	cp := h.checkpoint()
+	h.saved = cp.id
+
+	return cp
+}
+
+// Dirty returns true if the history's state differs from the one last marked as saved, or from
+// its initial state if it has never been marked. Undoing or redoing back to the saved state makes
+// the history clean again. Commands in an open group make the history dirty.
+func (h *History[T]) Dirty() bool {
+	if h == nil {
+		return false
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return len(h.group) > 0 || h.current() != h.saved
+}
+
+// Clear discards every entry in the history without undoing or redoing anything, so the current
+// state becomes the oldest one that can be reached. Checkpoints for other states can no longer be
+// reverted to. If a group is open, this returns ErrGroupOpen.
+func (h *History[T]) Clear() error {
+	if h == nil {
+		return nil
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.depth > 0 {
+		return ErrGroupOpen
+	}
+
+	h.base = h.current()
+	h.stack().Clear()
+	h.redo.Clear()
+
+	return nil
+}
+
+// current returns the ID of the history's current state. The caller must hold the history's lock.
+func (h *History[T]) current() uint64 {
+	if top, err := h.stack().PeekAt(0); err == nil {
+		return top.id
+	}
+
+	return h.base
+}
+
+// stack returns the stack of entries that can be undone, creating it if necessary. The caller must
+// hold the history's lock.
+func (h *History[T]) stack() *stack.Stack[entry[T]] {
+	if h.undo == nil {
+		h.undo = new(stack.Stack[entry[T]])
+	}
+
+	return h.undo
+}
@@ -0,0 +1,42 @@
+package undo_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/green-aloe/utilities/undo"
+)
+
+func ExampleHistory() {
+	var text string
+
+	h := undo.New[string](100)
+	h.Apply = func(s string) error {
+		text += s
+		return nil
+	}
+	h.Revert = func(s string) error {
+		text = strings.TrimSuffix(text, s)
+		return nil
+	}
+
+	h.Do("Hello")
+	h.MarkSaved()
+
+	h.BeginGroup()
+	h.Do(", ")
+	h.Do("world")
+	h.EndGroup()
+	fmt.Println(text, h.Dirty())
+
+	h.Undo()
+	fmt.Println(text, h.Dirty())
+
+	h.Redo()
+	fmt.Println(text, h.Dirty())
+
+	// Output:
+	// Hello, world true
+	// Hello false
+	// Hello, world true
+}
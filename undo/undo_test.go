@@ -0,0 +1,526 @@
+package undo
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// doc is a document edited through a History of edits.
+type doc struct {
+	text  strings.Builder
+	mutex sync.Mutex
+}
+
+// edit appends or removes text at the end of a document.
+type edit string
+
+// newDoc returns an empty document and a history that edits it by appending and removing text.
+func newDoc(maxDepth int) (*doc, *History[edit]) {
+	d := new(doc)
+	h := New[edit](maxDepth)
+	h.Apply = func(e edit) error {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		d.text.WriteString(string(e))
+		return nil
+	}
+	h.Revert = func(e edit) error {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		s := d.text.String()
+		if !strings.HasSuffix(s, string(e)) {
+			return errors.New("document doesn't end with edit")
+		}
+		d.text.Reset()
+		d.text.WriteString(strings.TrimSuffix(s, string(e)))
+		return nil
+	}
+	return d, h
+}
+
+func (d *doc) String() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.text.String()
+}
+
+// Test_History_Do tests that History's Do method applies and records commands.
+func Test_History_Do(t *testing.T) {
+	t.Run("nil history", func(t *testing.T) {
+		var h *History[int]
+		require.NoError(t, h.Do(1))
+		require.False(t, h.CanUndo())
+		require.False(t, h.CanRedo())
+		require.ErrorIs(t, h.Undo(), ErrNothingToUndo)
+		require.ErrorIs(t, h.Redo(), ErrNothingToRedo)
+		require.NotPanics(t, func() { h.BeginGroup() })
+		require.NotPanics(t, func() { h.EndGroup() })
+		require.ErrorIs(t, h.RevertTo(h.Checkpoint()), ErrUnknownCheckpoint)
+		require.Zero(t, h.MarkSaved())
+		require.False(t, h.Dirty())
+		require.NoError(t, h.Clear())
+	})
+
+	t.Run("zero history", func(t *testing.T) {
+		var h History[int]
+		require.NoError(t, h.Do(1))
+		require.NoError(t, h.Do(2))
+		require.True(t, h.CanUndo())
+
+		require.NoError(t, h.Undo())
+		require.NoError(t, h.Undo())
+		require.ErrorIs(t, h.Undo(), ErrNothingToUndo)
+		require.NoError(t, h.Redo())
+		require.True(t, h.CanUndo())
+		require.True(t, h.CanRedo())
+	})
+
+	t.Run("apply error", func(t *testing.T) {
+		d, h := newDoc(0)
+		errApply := errors.New("apply")
+		apply := h.Apply
+		h.Apply = func(e edit) error {
+			if e == "bad" {
+				return errApply
+			}
+			return apply(e)
+		}
+
+		require.NoError(t, h.Do("a"))
+		require.NoError(t, h.Undo())
+		require.ErrorIs(t, h.Do("bad"), errApply)
+
+		// The failed command wasn't recorded, and it didn't discard the redo history.
+		require.True(t, h.CanRedo())
+		require.NoError(t, h.Redo())
+		require.Equal(t, "a", d.String())
+		require.NoError(t, h.Undo())
+		require.False(t, h.CanUndo())
+	})
+
+	t.Run("discards redo", func(t *testing.T) {
+		d, h := newDoc(0)
+		require.NoError(t, h.Do("a"))
+		require.NoError(t, h.Do("b"))
+		require.NoError(t, h.Undo())
+		require.True(t, h.CanRedo())
+
+		require.NoError(t, h.Do("c"))
+		require.False(t, h.CanRedo())
+		require.ErrorIs(t, h.Redo(), ErrNothingToRedo)
+		require.Equal(t, "ac", d.String())
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		d, h := newDoc(3)
+		for _, e := range []edit{"a", "b", "c", "d", "e"} {
+			require.NoError(t, h.Do(e))
+		}
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, h.Undo())
+		}
+		require.ErrorIs(t, h.Undo(), ErrNothingToUndo)
+		require.Equal(t, "ab", d.String())
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, h.Redo())
+		}
+		require.Equal(t, "abcde", d.String())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		d, h := newDoc(0)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				require.NoError(t, h.Do("x"))
+				if i%2 == 0 {
+					h.Undo()
+				} else {
+					h.Redo()
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		for h.CanUndo() {
+			require.NoError(t, h.Undo())
+		}
+		require.Empty(t, d.String())
+	})
+}
+
+// Test_History_Undo tests that History's Undo and Redo methods move through the history.
+func Test_History_Undo(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		d, h := newDoc(0)
+		for _, e := range []edit{"a", "b", "c"} {
+			require.NoError(t, h.Do(e))
+		}
+
+		for _, want := range []string{"ab", "a", ""} {
+			require.NoError(t, h.Undo())
+			require.Equal(t, want, d.String())
+		}
+		require.ErrorIs(t, h.Undo(), ErrNothingToUndo)
+
+		for _, want := range []string{"a", "ab", "abc"} {
+			require.NoError(t, h.Redo())
+			require.Equal(t, want, d.String())
+		}
+		require.ErrorIs(t, h.Redo(), ErrNothingToRedo)
+	})
+
+	t.Run("revert error", func(t *testing.T) {
+		d, h := newDoc(0)
+		h.BeginGroup()
+		require.NoError(t, h.Do("a"))
+		require.NoError(t, h.Do("b"))
+		require.NoError(t, h.Do("c"))
+		h.EndGroup()
+
+		// Undoing "a" fails after "c" and "b" are reverted, so they're applied again.
+		errRevert := errors.New("revert")
+		revert := h.Revert
+		h.Revert = func(e edit) error {
+			if e == "a" {
+				return errRevert
+			}
+			return revert(e)
+		}
+		require.ErrorIs(t, h.Undo(), errRevert)
+		require.Equal(t, "abc", d.String())
+		require.True(t, h.CanUndo())
+		require.False(t, h.CanRedo())
+
+		h.Revert = revert
+		require.NoError(t, h.Undo())
+		require.Empty(t, d.String())
+	})
+
+	t.Run("redo error", func(t *testing.T) {
+		d, h := newDoc(0)
+		h.BeginGroup()
+		require.NoError(t, h.Do("a"))
+		require.NoError(t, h.Do("b"))
+		h.EndGroup()
+		require.NoError(t, h.Undo())
+
+		errApply := errors.New("apply")
+		apply := h.Apply
+		h.Apply = func(e edit) error {
+			if e == "b" {
+				return errApply
+			}
+			return apply(e)
+		}
+		require.ErrorIs(t, h.Redo(), errApply)
+		require.Empty(t, d.String())
+		require.True(t, h.CanRedo())
+
+		h.Apply = apply
+		require.NoError(t, h.Redo())
+		require.Equal(t, "ab", d.String())
+	})
+}
+
+// Test_History_Group tests that commands done in a group are undone and redone together.
+func Test_History_Group(t *testing.T) {
+	t.Run("one entry", func(t *testing.T) {
+		d, h := newDoc(0)
+		require.NoError(t, h.Do("a"))
+
+		h.BeginGroup()
+		require.NoError(t, h.Do("b"))
+		require.NoError(t, h.Do("c"))
+		require.ErrorIs(t, h.Undo(), ErrGroupOpen)
+		require.ErrorIs(t, h.Redo(), ErrGroupOpen)
+		require.ErrorIs(t, h.Clear(), ErrGroupOpen)
+		require.ErrorIs(t, h.RevertTo(Checkpoint{}), ErrGroupOpen)
+		h.EndGroup()
+
+		require.NoError(t, h.Undo())
+		require.Equal(t, "a", d.String())
+		require.NoError(t, h.Redo())
+		require.Equal(t, "abc", d.String())
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		d, h := newDoc(0)
+		h.BeginGroup()
+		require.NoError(t, h.Do("a"))
+		h.BeginGroup()
+		require.NoError(t, h.Do("b"))
+		h.EndGroup()
+		require.ErrorIs(t, h.Undo(), ErrGroupOpen)
+		require.NoError(t, h.Do("c"))
+		h.EndGroup()
+		h.EndGroup()
+
+		require.NoError(t, h.Undo())
+		require.Empty(t, d.String())
+		require.False(t, h.CanUndo())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, h := newDoc(0)
+		h.BeginGroup()
+		h.EndGroup()
+		require.False(t, h.CanUndo())
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		d, h := newDoc(2)
+		for _, group := range [][]edit{{"a", "b"}, {"c"}, {"d", "e"}} {
+			h.BeginGroup()
+			for _, e := range group {
+				require.NoError(t, h.Do(e))
+			}
+			h.EndGroup()
+		}
+
+		require.NoError(t, h.Undo())
+		require.NoError(t, h.Undo())
+		require.ErrorIs(t, h.Undo(), ErrNothingToUndo)
+		require.Equal(t, "ab", d.String())
+	})
+}
+
+// Test_History_Merge tests that consecutive commands are merged when Merge allows it.
+func Test_History_Merge(t *testing.T) {
+	// Letters merge with letters, and anything else stands alone.
+	letters := func(prev, next edit) (edit, bool) {
+		if strings.Trim(string(prev+next), "abcdefghijklmnopqrstuvwxyz") != "" {
+			return "", false
+		}
+		return prev + next, true
+	}
+
+	t.Run("consecutive", func(t *testing.T) {
+		d, h := newDoc(0)
+		h.Merge = letters
+		for _, e := range []edit{"a", "b", "c", " ", "d", "e"} {
+			require.NoError(t, h.Do(e))
+		}
+
+		for _, want := range []string{"abc ", "abc", ""} {
+			require.NoError(t, h.Undo())
+			require.Equal(t, want, d.String())
+		}
+		require.False(t, h.CanUndo())
+
+		require.NoError(t, h.Redo())
+		require.Equal(t, "abc", d.String())
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		d, h := newDoc(0)
+		h.Merge = letters
+		require.NoError(t, h.Do("a"))
+
+		h.BeginGroup()
+		require.NoError(t, h.Do("b"))
+		require.NoError(t, h.Do("c"))
+		require.NoError(t, h.Do("1"))
+		h.EndGroup()
+		require.Len(t, h.group, 0)
+
+		require.NoError(t, h.Do("d"))
+
+		// Groups don't merge with commands outside them.
+		require.NoError(t, h.Undo())
+		require.Equal(t, "abc1", d.String())
+		require.NoError(t, h.Undo())
+		require.Equal(t, "a", d.String())
+	})
+
+	t.Run("checkpoint", func(t *testing.T) {
+		d, h := newDoc(0)
+		h.Merge = letters
+		require.NoError(t, h.Do("a"))
+		cp := h.Checkpoint()
+		require.NoError(t, h.Do("b"))
+		require.NoError(t, h.Do("c"))
+
+		require.NoError(t, h.Undo())
+		require.Equal(t, "a", d.String())
+		require.NoError(t, h.Redo())
+		require.NoError(t, h.RevertTo(cp))
+		require.Equal(t, "a", d.String())
+	})
+}
+
+// Test_History_Checkpoint tests that a history can revert to checkpoints.
+func Test_History_Checkpoint(t *testing.T) {
+	t.Run("backward and forward", func(t *testing.T) {
+		d, h := newDoc(0)
+		start := h.Checkpoint()
+		require.NoError(t, h.Do("a"))
+		require.NoError(t, h.Do("b"))
+		middle := h.Checkpoint()
+		require.NoError(t, h.Do("c"))
+		require.NoError(t, h.Do("d"))
+		end := h.Checkpoint()
+
+		require.NoError(t, h.RevertTo(middle))
+		require.Equal(t, "ab", d.String())
+		require.NoError(t, h.RevertTo(start))
+		require.Empty(t, d.String())
+		require.NoError(t, h.RevertTo(end))
+		require.Equal(t, "abcd", d.String())
+		require.NoError(t, h.RevertTo(end))
+		require.Equal(t, "abcd", d.String())
+		require.NoError(t, h.RevertTo(middle))
+		require.Equal(t, "ab", d.String())
+	})
+
+	t.Run("discarded", func(t *testing.T) {
+		d, h := newDoc(0)
+		require.NoError(t, h.Do("a"))
+		require.NoError(t, h.Do("b"))
+		cp := h.Checkpoint()
+		require.NoError(t, h.Undo())
+		require.NoError(t, h.Do("c"))
+
+		require.ErrorIs(t, h.RevertTo(cp), ErrUnknownCheckpoint)
+		require.Equal(t, "ac", d.String())
+	})
+
+	t.Run("evicted", func(t *testing.T) {
+		d, h := newDoc(2)
+		start := h.Checkpoint()
+		require.NoError(t, h.Do("a"))
+		first := h.Checkpoint()
+		require.NoError(t, h.Do("b"))
+		require.NoError(t, h.Do("c"))
+
+		require.ErrorIs(t, h.RevertTo(start), ErrUnknownCheckpoint)
+		require.Equal(t, "abc", d.String())
+
+		// The state after the evicted entry is the oldest one left.
+		require.NoError(t, h.RevertTo(first))
+		require.Equal(t, "a", d.String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		d, h := newDoc(0)
+		start := h.Checkpoint()
+		require.NoError(t, h.Do("a"))
+		require.NoError(t, h.Do("b"))
+		require.NoError(t, h.Do("c"))
+
+		errRevert := errors.New("revert")
+		revert := h.Revert
+		h.Revert = func(e edit) error {
+			if e == "a" {
+				return errRevert
+			}
+			return revert(e)
+		}
+		require.ErrorIs(t, h.RevertTo(start), errRevert)
+		require.Equal(t, "a", d.String())
+	})
+
+	t.Run("cleared", func(t *testing.T) {
+		d, h := newDoc(0)
+		start := h.Checkpoint()
+		require.NoError(t, h.Do("a"))
+		require.NoError(t, h.Do("b"))
+		require.NoError(t, h.Undo())
+		now := h.Checkpoint()
+
+		require.NoError(t, h.Clear())
+		require.False(t, h.CanUndo())
+		require.False(t, h.CanRedo())
+		require.ErrorIs(t, h.RevertTo(start), ErrUnknownCheckpoint)
+		require.NoError(t, h.RevertTo(now))
+		require.Equal(t, "a", d.String())
+
+		require.NoError(t, h.Do("c"))
+		require.NoError(t, h.RevertTo(now))
+		require.Equal(t, "a", d.String())
+	})
+}
+
+// Test_History_Dirty tests that a history tracks whether it has changed since it was last saved.
+func Test_History_Dirty(t *testing.T) {
+	t.Run("never saved", func(t *testing.T) {
+		_, h := newDoc(0)
+		require.False(t, h.Dirty())
+		require.NoError(t, h.Do("a"))
+		require.True(t, h.Dirty())
+		require.NoError(t, h.Undo())
+		require.False(t, h.Dirty())
+	})
+
+	t.Run("saved", func(t *testing.T) {
+		_, h := newDoc(0)
+		require.NoError(t, h.Do("a"))
+		require.NoError(t, h.Do("b"))
+		h.MarkSaved()
+		require.False(t, h.Dirty())
+
+		require.NoError(t, h.Undo())
+		require.True(t, h.Dirty())
+		require.NoError(t, h.Redo())
+		require.False(t, h.Dirty())
+
+		require.NoError(t, h.Do("c"))
+		require.True(t, h.Dirty())
+		require.NoError(t, h.Undo())
+		require.False(t, h.Dirty())
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		_, h := newDoc(0)
+		require.NoError(t, h.Do("a"))
+		h.MarkSaved()
+		require.NoError(t, h.Undo())
+		require.NoError(t, h.Do("b"))
+		require.True(t, h.Dirty())
+		require.NoError(t, h.Undo())
+		require.True(t, h.Dirty())
+	})
+
+	t.Run("merged", func(t *testing.T) {
+		_, h := newDoc(0)
+		h.Merge = func(prev, next edit) (edit, bool) { return prev + next, true }
+		require.NoError(t, h.Do("a"))
+		h.MarkSaved()
+		require.NoError(t, h.Do("b"))
+		require.True(t, h.Dirty())
+		require.NoError(t, h.Undo())
+		require.False(t, h.Dirty())
+	})
+
+	t.Run("group", func(t *testing.T) {
+		_, h := newDoc(0)
+		h.BeginGroup()
+		require.NoError(t, h.Do("a"))
+		require.True(t, h.Dirty())
+		h.EndGroup()
+		require.True(t, h.Dirty())
+		h.MarkSaved()
+		require.False(t, h.Dirty())
+	})
+
+	t.Run("cleared", func(t *testing.T) {
+		_, h := newDoc(0)
+		require.NoError(t, h.Do("a"))
+		h.MarkSaved()
+		require.NoError(t, h.Clear())
+		require.False(t, h.Dirty())
+
+		require.NoError(t, h.Do("b"))
+		require.NoError(t, h.Clear())
+		require.True(t, h.Dirty())
+	})
+}